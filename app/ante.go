@@ -1,11 +1,13 @@
 package app
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/params"
 
 	"github.com/cosmos/ethermint/crypto"
 	"github.com/cosmos/ethermint/types"
@@ -13,6 +15,7 @@ import (
 
 	ethcmn "github.com/ethereum/go-ethereum/common"
 	ethcore "github.com/ethereum/go-ethereum/core"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
 
 	tmcrypto "github.com/tendermint/tendermint/crypto"
 )
@@ -29,7 +32,10 @@ const (
 //
 // NOTE: The EVM will already consume (intrinsic) gas for signature verification
 // and covering input size as well as handling nonce incrementing.
-func NewAnteHandler(ak auth.AccountKeeper, fck auth.FeeCollectionKeeper) sdk.AnteHandler {
+func NewAnteHandler(
+	ak auth.AccountKeeper, fck auth.FeeCollectionKeeper, evmParamSpace params.Subspace,
+) sdk.AnteHandler {
+
 	return func(
 		ctx sdk.Context, tx sdk.Tx, sim bool,
 	) (newCtx sdk.Context, res sdk.Result, abort bool) {
@@ -39,7 +45,7 @@ func NewAnteHandler(ak auth.AccountKeeper, fck auth.FeeCollectionKeeper) sdk.Ant
 			return sdkAnteHandler(ctx, ak, fck, castTx, sim)
 
 		case *evmtypes.EthereumTxMsg:
-			return ethAnteHandler(ctx, castTx, ak)
+			return ethAnteHandler(ctx, castTx, ak, evmParamSpace)
 
 		default:
 			return ctx, sdk.ErrInternal(fmt.Sprintf("transaction type invalid: %T", tx)).Result(), true
@@ -140,8 +146,16 @@ func processSig(
 	}
 
 	consumeSigGas(ctx.GasMeter(), pubKey)
-	if !sim && !pubKey.VerifyBytes(signBytes, sig.Signature) {
-		return nil, sdk.ErrUnauthorized("signature verification failed").Result()
+	if !sim {
+		// reject malleable and non-canonical signatures with the same rules as
+		// Ethereum transactions prior to verification
+		if err := crypto.ValidateSignature(sig.Signature); err != nil {
+			return nil, sdk.ErrUnauthorized(err.Error()).Result()
+		}
+
+		if !pubKey.VerifyBytes(signBytes, sig.Signature) {
+			return nil, sdk.ErrUnauthorized("signature verification failed").Result()
+		}
 	}
 
 	err = acc.SetSequence(acc.GetSequence() + 1)
@@ -172,13 +186,13 @@ func consumeSigGas(meter sdk.GasMeter, pubkey tmcrypto.PubKey) {
 // perform the same series of checks. The distinction is made in CheckTx to
 // prevent spam and DoS attacks.
 func ethAnteHandler(
-	ctx sdk.Context, ethTxMsg *evmtypes.EthereumTxMsg, ak auth.AccountKeeper,
+	ctx sdk.Context, ethTxMsg *evmtypes.EthereumTxMsg, ak auth.AccountKeeper, evmParamSpace params.Subspace,
 ) (newCtx sdk.Context, res sdk.Result, abort bool) {
 
 	if ctx.IsCheckTx() {
 		// Only perform pre-message (Ethereum transaction) execution validation
 		// during CheckTx. Otherwise, during DeliverTx the EVM will handle them.
-		if res := validateEthTxCheckTx(ctx, ak, ethTxMsg); !res.IsOK() {
+		if res := validateEthTxCheckTx(ctx, ak, ethTxMsg, evmParamSpace); !res.IsOK() {
 			return newCtx, res, true
 		}
 	}
@@ -187,23 +201,27 @@ func ethAnteHandler(
 }
 
 func validateEthTxCheckTx(
-	ctx sdk.Context, ak auth.AccountKeeper, ethTxMsg *evmtypes.EthereumTxMsg,
+	ctx sdk.Context, ak auth.AccountKeeper, ethTxMsg *evmtypes.EthereumTxMsg, evmParamSpace params.Subspace,
 ) sdk.Result {
 
-	// parse the chainID from a string to a base-10 integer
-	chainID, ok := new(big.Int).SetString(ctx.ChainID(), 10)
-	if !ok {
-		return types.ErrInvalidChainID(fmt.Sprintf("invalid chainID: %s", ctx.ChainID())).Result()
+	evmParams := evmtypes.GetParams(ctx, evmParamSpace)
+	denom := evmParams.EVMDenom
+
+	// get the EIP-155 chain ID the transaction must be signed with
+	chainID, err := evmtypes.GetChainID(ctx, evmParams.ChainConfig)
+	if err != nil {
+		return types.ErrInvalidChainID(err.Error()).Result()
 	}
 
 	// Validate sufficient fees have been provided that meet a minimum threshold
 	// defined by the proposer (for mempool purposes during CheckTx).
-	if res := ensureSufficientMempoolFees(ctx, ethTxMsg); !res.IsOK() {
+	if res := ensureSufficientMempoolFees(ctx, ethTxMsg, denom); !res.IsOK() {
 		return res
 	}
 
 	// validate enough intrinsic gas
-	if res := validateIntrinsicGas(ethTxMsg); !res.IsOK() {
+	homestead := evmParams.ChainConfig.EthereumConfig(chainID).IsHomestead(big.NewInt(ctx.BlockHeight()))
+	if res := validateIntrinsicGas(ethTxMsg, homestead); !res.IsOK() {
 		return res
 	}
 
@@ -213,8 +231,21 @@ func validateEthTxCheckTx(
 		return sdk.ErrUnauthorized("signature verification failed").Result()
 	}
 
+	// validate the sender is allowed to deploy or call contracts
+	contractCall := ethTxMsg.To() != nil && isContract(ctx, ak, *ethTxMsg.To())
+	if err := evmParams.ValidateSender(signer, ethTxMsg.To() == nil, contractCall); err != nil {
+		return sdk.ErrUnauthorized(err.Error()).Result()
+	}
+
+	// validate the init code of a contract creation only holds allowed opcodes
+	if ethTxMsg.To() == nil {
+		if err := evmParams.ValidateOpcodes(ethTxMsg.Data.Payload); err != nil {
+			return sdk.ErrUnauthorized(fmt.Sprintf("invalid init code: %s", err)).Result()
+		}
+	}
+
 	// validate account (nonce and balance checks)
-	if res := validateAccount(ctx, ak, ethTxMsg, signer); !res.IsOK() {
+	if res := validateAccount(ctx, ak, ethTxMsg, signer, denom); !res.IsOK() {
 		return res
 	}
 
@@ -225,9 +256,10 @@ func validateEthTxCheckTx(
 // cover intrinsic gas. Intrinsic gas for a transaction is the amount of gas
 // that the transaction uses before the transaction is executed. The gas is a
 // constant value of 21000 plus any cost inccured by additional bytes of data
-// supplied with the transaction.
-func validateIntrinsicGas(ethTxMsg *evmtypes.EthereumTxMsg) sdk.Result {
-	gas, err := ethcore.IntrinsicGas(ethTxMsg.Data.Payload, ethTxMsg.To() == nil, false)
+// supplied with the transaction. Contract creation costs more once the
+// Homestead fork is activated.
+func validateIntrinsicGas(ethTxMsg *evmtypes.EthereumTxMsg, homestead bool) sdk.Result {
+	gas, err := ethcore.IntrinsicGas(ethTxMsg.Data.Payload, ethTxMsg.To() == nil, homestead)
 	if err != nil {
 		return sdk.ErrInternal(fmt.Sprintf("failed to compute intrinsic gas cost: %s", err)).Result()
 	}
@@ -241,10 +273,22 @@ func validateIntrinsicGas(ethTxMsg *evmtypes.EthereumTxMsg) sdk.Result {
 	return sdk.Result{}
 }
 
+// isContract returns true if the account at the given address holds contract
+// code.
+func isContract(ctx sdk.Context, ak auth.AccountKeeper, addr ethcmn.Address) bool {
+	acc, ok := ak.GetAccount(ctx, sdk.AccAddress(addr.Bytes())).(*types.Account)
+	if !ok {
+		return false
+	}
+
+	return len(acc.CodeHash) > 0 && !bytes.Equal(acc.CodeHash, ethcrypto.Keccak256(nil))
+}
+
 // validateAccount validates the account nonce and that the account has enough
-// funds to cover the tx cost.
+// funds of the EVM denomination to cover the tx cost.
 func validateAccount(
-	ctx sdk.Context, ak auth.AccountKeeper, ethTxMsg *evmtypes.EthereumTxMsg, signer ethcmn.Address,
+	ctx sdk.Context, ak auth.AccountKeeper, ethTxMsg *evmtypes.EthereumTxMsg,
+	signer ethcmn.Address, denom string,
 ) sdk.Result {
 
 	acc := ak.GetAccount(ctx, sdk.AccAddress(signer.Bytes()))
@@ -266,7 +310,7 @@ func validateAccount(
 	}
 
 	// validate sender has enough funds
-	balance := acc.GetCoins().AmountOf(types.DenomDefault)
+	balance := acc.GetCoins().AmountOf(denom)
 	if balance.BigInt().Cmp(ethTxMsg.Cost()) < 0 {
 		return sdk.ErrInsufficientFunds(
 			fmt.Sprintf("insufficient funds: %s < %s", balance, ethTxMsg.Cost()),
@@ -281,9 +325,9 @@ func validateAccount(
 // proposer.
 //
 // NOTE: This should only be ran during a CheckTx mode.
-func ensureSufficientMempoolFees(ctx sdk.Context, ethTxMsg *evmtypes.EthereumTxMsg) sdk.Result {
+func ensureSufficientMempoolFees(ctx sdk.Context, ethTxMsg *evmtypes.EthereumTxMsg, denom string) sdk.Result {
 	// fee = GP * GL
-	fee := sdk.Coins{sdk.NewInt64Coin(types.DenomDefault, ethTxMsg.Fee().Int64())}
+	fee := sdk.Coins{sdk.NewInt64Coin(denom, ethTxMsg.Fee().Int64())}
 
 	// it is assumed that the minimum fees will only include the single valid denom
 	if !ctx.MinimumFees().IsZero() && !fee.IsAllGTE(ctx.MinimumFees()) {
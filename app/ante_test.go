@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
 	"github.com/stretchr/testify/require"
 	tmcrypto "github.com/tendermint/tendermint/crypto"
 
@@ -104,6 +105,14 @@ func TestValidTx(t *testing.T) {
 	acc2 = input.accKeeper.GetAccount(input.ctx, addr2)
 	require.Equal(t, accSeqs[0]+1, acc1.GetSequence())
 	require.Equal(t, accSeqs[1]+1, acc2.GetSequence())
+
+	// require the stored public keys to decode back to working keys
+	require.True(t, priv1.PubKey().Equals(acc1.GetPubKey()))
+	require.True(t, priv2.PubKey().Equals(acc2.GetPubKey()))
+
+	sig, err := priv1.Sign([]byte("test"))
+	require.NoError(t, err)
+	require.True(t, acc1.GetPubKey().VerifyBytes([]byte("test"), sig))
 }
 
 func TestSDKInvalidSigs(t *testing.T) {
@@ -155,6 +164,17 @@ func TestSDKInvalidSigs(t *testing.T) {
 
 	tx = newTestSDKTx(input.ctx, msgs, privKeys, accNums, accSeqs, fee)
 	requireInvalidTx(t, input.anteHandler, input.ctx, tx, false, sdk.CodeUnknownAddress)
+
+	// require validation failure with a malleated (high-S) signature
+	msgs = []sdk.Msg{msg1}
+
+	privKeys = []tmcrypto.PrivKey{priv1, priv2}
+	accNums = []uint64{acc1.GetAccountNumber(), acc2.GetAccountNumber()}
+	accSeqs = []uint64{acc1.GetSequence(), acc2.GetSequence()}
+
+	stdTx := newTestSDKTx(input.ctx, msgs, privKeys, accNums, accSeqs, fee).(auth.StdTx)
+	stdTx.Signatures[0].Signature = malleateSig(stdTx.Signatures[0].Signature)
+	requireInvalidTx(t, input.anteHandler, input.ctx, stdTx, false, sdk.CodeUnauthorized)
 }
 
 func TestSDKInvalidAcc(t *testing.T) {
@@ -245,6 +265,33 @@ func TestEthInsufficientBalance(t *testing.T) {
 	requireInvalidTx(t, input.anteHandler, input.ctx, tx, false, sdk.CodeInsufficientFunds)
 }
 
+func TestEthInsufficientBalanceEVMDenom(t *testing.T) {
+	input := newTestSetup()
+	input.ctx = input.ctx.WithBlockHeight(1)
+
+	addr1, priv1 := newTestAddrKey()
+	addr2, _ := newTestAddrKey()
+
+	acc := input.accKeeper.NewAccountWithAddress(input.ctx, addr1)
+	acc.SetCoins(newTestCoins())
+	input.accKeeper.SetAccount(input.ctx, acc)
+
+	to := ethcmn.BytesToAddress(addr2.Bytes())
+	amt := big.NewInt(32)
+	gas := big.NewInt(20)
+	ethMsg := evmtypes.NewEthereumTxMsg(0, to, amt, 22000, gas, []byte("test"))
+
+	tx := newTestEthTx(input.ctx, ethMsg, priv1)
+	requireValidTx(t, input.anteHandler, input.ctx, tx, false)
+
+	// require the balance check to use the EVM denomination set in the params
+	params := evmtypes.DefaultParams()
+	params.EVMDenom = "aphoton"
+	input.evmParams.SetParamSet(input.ctx, &params)
+
+	requireInvalidTx(t, input.anteHandler, input.ctx, tx, false, sdk.CodeInsufficientFunds)
+}
+
 func TestEthInvalidIntrinsicGas(t *testing.T) {
 	input := newTestSetup()
 	input.ctx = input.ctx.WithBlockHeight(1)
@@ -310,3 +357,67 @@ func TestEthInvalidChainID(t *testing.T) {
 	ctx := input.ctx.WithChainID("bad-chain-id")
 	requireInvalidTx(t, input.anteHandler, ctx, tx, false, types.CodeInvalidChainID)
 }
+
+func TestEthDeployerNotAllowed(t *testing.T) {
+	input := newTestSetup()
+	input.ctx = input.ctx.WithBlockHeight(1)
+
+	addr1, priv1 := newTestAddrKey()
+	addr2, _ := newTestAddrKey()
+
+	acc := input.accKeeper.NewAccountWithAddress(input.ctx, addr1)
+	acc.SetCoins(newTestCoins())
+	input.accKeeper.SetAccount(input.ctx, acc)
+
+	amt := big.NewInt(0)
+	gas := big.NewInt(20)
+	ethMsg := evmtypes.NewEthereumTxMsgContract(0, amt, 100000, gas, []byte("test"))
+
+	tx := newTestEthTx(input.ctx, ethMsg, priv1)
+	requireValidTx(t, input.anteHandler, input.ctx, tx, false)
+
+	// require a contract creation from an account missing from the deployer
+	// allow-list to be rejected
+	params := evmtypes.DefaultParams()
+	params.AllowedDeployers = []string{ethcmn.BytesToAddress(addr2.Bytes()).Hex()}
+	input.evmParams.SetParamSet(input.ctx, &params)
+
+	requireInvalidTx(t, input.anteHandler, input.ctx, tx, false, sdk.CodeUnauthorized)
+
+	params.AllowedDeployers = append(params.AllowedDeployers, ethcmn.BytesToAddress(addr1.Bytes()).Hex())
+	input.evmParams.SetParamSet(input.ctx, &params)
+
+	requireValidTx(t, input.anteHandler, input.ctx, tx, false)
+}
+
+func TestEthInitCodeNotAllowed(t *testing.T) {
+	input := newTestSetup()
+	input.ctx = input.ctx.WithBlockHeight(1)
+
+	addr1, priv1 := newTestAddrKey()
+
+	acc := input.accKeeper.NewAccountWithAddress(input.ctx, addr1)
+	acc.SetCoins(newTestCoins())
+	input.accKeeper.SetAccount(input.ctx, acc)
+
+	params := evmtypes.DefaultParams()
+	params.AllowedOpcodes = []string{"PUSH1", "RETURN"}
+	input.evmParams.SetParamSet(input.ctx, &params)
+
+	amt := big.NewInt(0)
+	gas := big.NewInt(20)
+
+	// require a contract creation with init code holding a disallowed opcode to
+	// be rejected
+	initCode := []byte{byte(ethvm.PUSH1), 0, byte(ethvm.SELFDESTRUCT)}
+	ethMsg := evmtypes.NewEthereumTxMsgContract(0, amt, 100000, gas, initCode)
+
+	tx := newTestEthTx(input.ctx, ethMsg, priv1)
+	requireInvalidTx(t, input.anteHandler, input.ctx, tx, false, sdk.CodeUnauthorized)
+
+	initCode = []byte{byte(ethvm.PUSH1), 0, byte(ethvm.PUSH1), 0, byte(ethvm.RETURN)}
+	ethMsg = evmtypes.NewEthereumTxMsgContract(0, amt, 100000, gas, initCode)
+
+	tx = newTestEthTx(input.ctx, ethMsg, priv1)
+	requireValidTx(t, input.anteHandler, input.ctx, tx, false)
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/stake"
 
 	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/evm"
 	evmtypes "github.com/cosmos/ethermint/x/evm/types"
 
 	"github.com/pkg/errors"
@@ -28,12 +30,16 @@ const appName = "Ethermint"
 var (
 	storeKeyAccount     = sdk.NewKVStoreKey("acc")
 	storeKeyStorage     = sdk.NewKVStoreKey("contract_storage")
+	storeKeyCode        = sdk.NewKVStoreKey("contract_code")
+	storeKeyHeader      = sdk.NewKVStoreKey("header")
+	storeKeyEVM         = sdk.NewKVStoreKey("evm")
 	storeKeyMain        = sdk.NewKVStoreKey("main")
 	storeKeyStake       = sdk.NewKVStoreKey("stake")
 	storeKeySlashing    = sdk.NewKVStoreKey("slashing")
 	storeKeyGov         = sdk.NewKVStoreKey("gov")
 	storeKeyFeeColl     = sdk.NewKVStoreKey("fee")
 	storeKeyParams      = sdk.NewKVStoreKey("params")
+	storeKeyTransStake  = sdk.NewTransientStoreKey("transient_stake")
 	storeKeyTransParams = sdk.NewTransientStoreKey("transient_params")
 )
 
@@ -48,12 +54,16 @@ type (
 
 		accountKey  *sdk.KVStoreKey
 		storageKey  *sdk.KVStoreKey
+		codeKey     *sdk.KVStoreKey
+		headerKey   *sdk.KVStoreKey
+		evmKey      *sdk.KVStoreKey
 		mainKey     *sdk.KVStoreKey
 		stakeKey    *sdk.KVStoreKey
 		slashingKey *sdk.KVStoreKey
 		govKey      *sdk.KVStoreKey
 		feeCollKey  *sdk.KVStoreKey
 		paramsKey   *sdk.KVStoreKey
+		tStakeKey   *sdk.TransientStoreKey
 		tParamsKey  *sdk.TransientStoreKey
 
 		accountKeeper  auth.AccountKeeper
@@ -63,6 +73,12 @@ type (
 		slashingKeeper slashing.Keeper
 		govKeeper      gov.Keeper
 		paramsKeeper   params.Keeper
+		evmKeeper      evm.Keeper
+
+		evmParamSpace params.Subspace
+
+		// invariants are asserted every invCheckPeriod blocks, if not zero
+		invCheckPeriod uint
 	}
 )
 
@@ -72,7 +88,13 @@ type (
 // TODO: Ethermint needs to support being bootstrapped as an application running
 // in a sovereign zone and as an application running with a shared security model.
 // For now, it will support only running as a sovereign application.
-func NewEthermintApp(logger tmlog.Logger, db dbm.DB, baseAppOpts ...func(*bam.BaseApp)) *EthermintApp {
+//
+// The application invariants are asserted every invCheckPeriod blocks, or
+// never if it is zero.
+func NewEthermintApp(
+	logger tmlog.Logger, db dbm.DB, invCheckPeriod uint, baseAppOpts ...func(*bam.BaseApp),
+) *EthermintApp {
+
 	cdc := CreateCodec()
 
 	baseApp := bam.NewBaseApp(appName, logger, db, evmtypes.TxDecoder(cdc), baseAppOpts...)
@@ -81,36 +103,59 @@ func NewEthermintApp(logger tmlog.Logger, db dbm.DB, baseAppOpts ...func(*bam.Ba
 		cdc:         cdc,
 		accountKey:  storeKeyAccount,
 		storageKey:  storeKeyStorage,
+		codeKey:     storeKeyCode,
+		headerKey:   storeKeyHeader,
+		evmKey:      storeKeyEVM,
 		mainKey:     storeKeyMain,
 		stakeKey:    storeKeyStake,
 		slashingKey: storeKeySlashing,
 		govKey:      storeKeyGov,
 		feeCollKey:  storeKeyFeeColl,
 		paramsKey:   storeKeyParams,
+		tStakeKey:   storeKeyTransStake,
 		tParamsKey:  storeKeyTransParams,
+
+		invCheckPeriod: invCheckPeriod,
 	}
 
 	app.paramsKeeper = params.NewKeeper(app.cdc, app.paramsKey, app.tParamsKey)
-	app.accountKeeper = auth.NewAccountKeeper(app.cdc, app.accountKey, auth.ProtoBaseAccount)
+	app.evmParamSpace = app.paramsKeeper.Subspace(evmtypes.DefaultParamspace).WithTypeTable(evmtypes.ParamTypeTable())
+	app.accountKeeper = auth.NewAccountKeeper(app.cdc, app.accountKey, types.ProtoBaseAccount)
 	app.feeCollKeeper = auth.NewFeeCollectionKeeper(app.cdc, app.feeCollKey)
+	app.coinKeeper = bank.NewBaseKeeper(app.accountKeeper)
+	app.stakeKeeper = stake.NewKeeper(
+		app.cdc, app.stakeKey, app.tStakeKey, app.coinKeeper,
+		app.paramsKeeper.Subspace(stake.DefaultParamspace), app.RegisterCodespace(stake.DefaultCodespace),
+	)
+	app.govKeeper = gov.NewKeeper(
+		app.cdc, app.govKey, app.paramsKeeper, app.paramsKeeper.Subspace(gov.DefaultParamspace),
+		app.coinKeeper, app.stakeKeeper, app.RegisterCodespace(gov.DefaultCodespace),
+	)
+	app.evmKeeper = evm.NewKeeper(
+		app.cdc, app.evmKey, app.storageKey, app.codeKey, app.headerKey,
+		app.accountKeeper, app.govKeeper, app.stakeKeeper, app.evmParamSpace,
+	)
 
 	// register message handlers
 	app.Router().
 		// TODO: add remaining routes
 		AddRoute("stake", stake.NewHandler(app.stakeKeeper)).
 		AddRoute("slashing", slashing.NewHandler(app.slashingKeeper)).
-		AddRoute("gov", gov.NewHandler(app.govKeeper))
+		AddRoute("gov", evm.NewGovHandler(app.evmKeeper, gov.NewHandler(app.govKeeper))).
+		AddRoute(evmtypes.RouteEthereumTxMsg, evm.NewHandler(app.evmKeeper))
 
 	// initialize the underlying ABCI BaseApp
 	app.SetInitChainer(app.initChainer)
 	app.SetBeginBlocker(app.BeginBlocker)
 	app.SetEndBlocker(app.EndBlocker)
-	app.SetAnteHandler(NewAnteHandler(app.accountKeeper, app.feeCollKeeper))
+	app.SetAnteHandler(NewAnteHandler(app.accountKeeper, app.feeCollKeeper, app.evmParamSpace))
 
 	app.MountStores(
 		app.mainKey, app.accountKey, app.stakeKey, app.slashingKey,
-		app.govKey, app.feeCollKey, app.paramsKey, app.storageKey,
+		app.govKey, app.feeCollKey, app.paramsKey, app.storageKey, app.codeKey,
+		app.headerKey, app.evmKey,
 	)
+	app.MountStore(app.tStakeKey, sdk.StoreTypeTransient)
 	app.MountStore(app.tParamsKey, sdk.StoreTypeTransient)
 
 	if err := app.LoadLatestVersion(app.accountKey); err != nil {
@@ -122,27 +167,54 @@ func NewEthermintApp(logger tmlog.Logger, db dbm.DB, baseAppOpts ...func(*bam.Ba
 }
 
 // BeginBlocker signals the beginning of a block. It performs application
-// updates on the start of every block.
+// updates on the start of every block. The Ethereum header of the block is
+// stored so that it is available to the EVM (e.g. the BLOCKHASH and COINBASE
+// opcodes).
 func (app *EthermintApp) BeginBlocker(
-	_ sdk.Context, _ abci.RequestBeginBlock,
+	ctx sdk.Context, req abci.RequestBeginBlock,
 ) abci.ResponseBeginBlock {
 
+	evm.BeginBlocker(ctx, req, app.evmKeeper)
+
 	return abci.ResponseBeginBlock{}
 }
 
 // EndBlocker signals the end of a block. It performs application updates on
-// the end of every block.
+// the end of every block. Governance proposals are tallied before the EVM
+// module applies the parameter changes of the passed ones. Invariants are
+// asserted periodically, as checking them walks through every account, and the
+// chain halts if one is broken.
 func (app *EthermintApp) EndBlocker(
-	_ sdk.Context, _ abci.RequestEndBlock,
+	ctx sdk.Context, req abci.RequestEndBlock,
 ) abci.ResponseEndBlock {
 
-	return abci.ResponseEndBlock{}
+	tags := gov.EndBlocker(ctx, app.govKeeper)
+	tags = tags.AppendTags(evm.EndBlocker(ctx, req, app.evmKeeper, app.feeCollKeeper))
+
+	if app.invCheckPeriod != 0 && ctx.BlockHeight()%int64(app.invCheckPeriod) == 0 {
+		app.assertInvariants(ctx)
+	}
+
+	return abci.ResponseEndBlock{Tags: tags}
+}
+
+// assertInvariants panics if any of the application invariants is broken.
+func (app *EthermintApp) assertInvariants(ctx sdk.Context) {
+	invariants := []evm.Invariant{
+		evm.SupplyInvariant(app.evmKeeper, app.feeCollKeeper),
+	}
+
+	for _, invariant := range invariants {
+		if err := invariant(ctx); err != nil {
+			panic(errors.Wrapf(err, "invariant broken at height %d", ctx.BlockHeight()))
+		}
+	}
 }
 
 // initChainer initializes the application blockchain with validators and other
 // state data from TendermintCore.
 func (app *EthermintApp) initChainer(
-	_ sdk.Context, req abci.RequestInitChain,
+	ctx sdk.Context, req abci.RequestInitChain,
 ) abci.ResponseInitChain {
 
 	var genesisState GenesisState
@@ -153,9 +225,35 @@ func (app *EthermintApp) initChainer(
 		panic(errors.Wrap(err, "failed to parse application genesis state"))
 	}
 
+	validators, err := stake.InitGenesis(ctx, app.stakeKeeper, genesisState.StakeData)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to initialize stake genesis state"))
+	}
+
+	gov.InitGenesis(ctx, app.govKeeper, genesisState.GovData)
+
+	// the EVM parameters are validated against the bond and deposit
+	// denominations, thus after the stake and gov genesis states are set
+	evmParams := evmtypes.DefaultParams()
+	if genesisState.EVMParams != nil {
+		evmParams = *genesisState.EVMParams
+	}
+
+	if err := app.evmKeeper.ValidateParams(ctx, evmParams); err != nil {
+		panic(errors.Wrap(err, "invalid EVM genesis parameters"))
+	}
+
+	app.evmParamSpace.SetParamSet(ctx, &evmParams)
+
 	// TODO: load the genesis accounts
 
-	return abci.ResponseInitChain{}
+	// no code is persisted yet, so all of its references are counted
+	app.evmKeeper.SetCodeRefsMigrated(ctx)
+
+	// the supply of the EVM denomination must be conserved from genesis on
+	app.evmKeeper.SetSupply(ctx, evm.TotalSupply(ctx, app.evmKeeper, app.feeCollKeeper))
+
+	return abci.ResponseInitChain{Validators: validators}
 }
 
 // CreateCodec creates a new amino wire codec and registers all the necessary
@@ -164,11 +262,14 @@ func CreateCodec() *codec.Codec {
 	cdc := codec.New()
 
 	// TODO: Add remaining codec registrations:
-	// bank, staking, distribution, slashing, and gov
+	// bank, distribution and slashing
 
+	stake.RegisterCodec(cdc)
+	gov.RegisterCodec(cdc)
 	crypto.RegisterCodec(cdc)
 	evmtypes.RegisterCodec(cdc)
 	auth.RegisterCodec(cdc)
+	types.RegisterCodec(cdc)
 	sdk.RegisterCodec(cdc)
 	codec.RegisterCrypto(cdc)
 
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"math/big"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+
+	"github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/evm"
+	evmtypes "github.com/cosmos/ethermint/x/evm/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestDeliverEthereumTx(t *testing.T) {
+	app := NewEthermintApp(log.NewNopLogger(), dbm.NewMemDB(), 1)
+
+	genesisState := GenesisState{
+		StakeData: stake.DefaultGenesisState(),
+		GovData:   gov.DefaultGenesisState(),
+	}
+
+	stateBytes, err := app.cdc.MarshalJSON(genesisState)
+	require.NoError(t, err)
+
+	app.InitChain(abci.RequestInitChain{ChainId: "3", AppStateBytes: stateBytes})
+
+	header := abci.Header{ChainID: "3", Height: 1}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+
+	addr, priv := newTestAddrKey()
+	recipient := ethcmn.HexToAddress("0x1")
+
+	// fund the sender and account for the funds in the expected supply
+	ctx := app.NewContext(false, header)
+
+	acc := app.accountKeeper.NewAccountWithAddress(ctx, addr)
+	require.NoError(t, acc.SetCoins(newTestCoins()))
+	app.accountKeeper.SetAccount(ctx, acc)
+
+	app.evmKeeper.SetSupply(ctx, evm.TotalSupply(ctx, app.evmKeeper, app.feeCollKeeper))
+
+	// require a value transfer to be applied by the EVM handler
+	msg := evmtypes.NewEthereumTxMsg(0, recipient, big.NewInt(100), 21000, big.NewInt(1), nil)
+	tx := newTestEthTx(ctx, msg, priv)
+
+	txBytes, err := app.cdc.MarshalBinaryLengthPrefixed(tx)
+	require.NoError(t, err)
+
+	res := app.DeliverTx(txBytes)
+	require.Equal(t, sdk.CodeOK, sdk.CodeType(res.Code), res.Log)
+
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+	app.Commit()
+
+	ctx = app.NewContext(true, abci.Header{ChainID: "3", Height: 1})
+
+	senderAcc := app.accountKeeper.GetAccount(ctx, addr)
+	require.IsType(t, &types.Account{}, senderAcc)
+	require.Equal(t, uint64(1), senderAcc.GetSequence())
+
+	recipientAcc := app.accountKeeper.GetAccount(ctx, sdk.AccAddress(recipient.Bytes()))
+	require.IsType(t, &types.Account{}, recipientAcc)
+	require.Equal(t, int64(100), recipientAcc.GetCoins().AmountOf(types.DenomDefault).Int64())
+}
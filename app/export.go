@@ -0,0 +1,45 @@
+package app
+
+import (
+	"fmt"
+
+	evmtypes "github.com/cosmos/ethermint/x/evm/types"
+
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// DumpState returns a raw Geth compatible dump of the EVM state (accounts, code
+// and storage) at the given height. The latest committed height is used if the
+// height is zero.
+//
+// NOTE: The application stores are reloaded at the given height, so the
+// application must not be used to process blocks afterwards.
+func (app *EthermintApp) DumpState(height int64) (ethstate.Dump, error) {
+	if height < 0 {
+		return ethstate.Dump{}, fmt.Errorf("invalid negative height %d", height)
+	}
+
+	if height > 0 && height != app.LastBlockHeight() {
+		if err := app.LoadVersion(height, app.accountKey); err != nil {
+			return ethstate.Dump{}, fmt.Errorf("failed to load state at height %d: %v", height, err)
+		}
+	}
+
+	ctx := app.NewContext(true, abci.Header{Height: app.LastBlockHeight()})
+
+	stateDB, err := evmtypes.NewCommitStateDB(
+		ctx, app.accountKeeper, app.evmParamSpace, app.storageKey, app.codeKey,
+	)
+	if err != nil {
+		return ethstate.Dump{}, err
+	}
+
+	dump := stateDB.RawDump()
+	if err := stateDB.Error(); err != nil {
+		return ethstate.Dump{}, err
+	}
+
+	return dump, nil
+}
@@ -2,14 +2,22 @@ package app
 
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+	"github.com/cosmos/cosmos-sdk/x/stake"
+
 	"github.com/cosmos/ethermint/types"
+	evmtypes "github.com/cosmos/ethermint/x/evm/types"
 )
 
 type (
 	// GenesisState defines the application's genesis state. It contains all the
-	// information required and accounts to initialize the blockchain.
+	// information required and accounts to initialize the blockchain. The
+	// default EVM module parameters are used if none are given.
 	GenesisState struct {
-		Accounts []GenesisAccount `json:"accounts"`
+		Accounts  []GenesisAccount   `json:"accounts"`
+		StakeData stake.GenesisState `json:"stake"`
+		GovData   gov.GenesisState   `json:"gov"`
+		EVMParams *evmtypes.Params   `json:"evm_params,omitempty"`
 	}
 
 	// GenesisAccount defines an account to be initialized in the genesis state.
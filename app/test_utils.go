@@ -9,6 +9,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/store"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/params"
 
 	"github.com/cosmos/ethermint/crypto"
 	"github.com/cosmos/ethermint/types"
@@ -27,6 +28,7 @@ type testSetup struct {
 	cdc         *codec.Codec
 	accKeeper   auth.AccountKeeper
 	feeKeeper   auth.FeeCollectionKeeper
+	evmParams   params.Subspace
 	anteHandler sdk.AnteHandler
 }
 
@@ -49,7 +51,9 @@ func newTestSetup() testSetup {
 
 	accKeeper := auth.NewAccountKeeper(cdc, authCapKey, auth.ProtoBaseAccount)
 	feeKeeper := auth.NewFeeCollectionKeeper(cdc, feeCapKey)
-	anteHandler := NewAnteHandler(accKeeper, feeKeeper)
+	paramsKeeper := params.NewKeeper(cdc, keyParams, tkeyParams)
+	evmParamSpace := paramsKeeper.Subspace(evmtypes.DefaultParamspace).WithTypeTable(evmtypes.ParamTypeTable())
+	anteHandler := NewAnteHandler(accKeeper, feeKeeper, evmParamSpace)
 
 	ctx := sdk.NewContext(
 		ms,
@@ -63,6 +67,7 @@ func newTestSetup() testSetup {
 		cdc:         cdc,
 		accKeeper:   accKeeper,
 		feeKeeper:   feeKeeper,
+		evmParams:   evmParamSpace,
 		anteHandler: anteHandler,
 	}
 }
@@ -124,3 +129,16 @@ func newTestEthTx(ctx sdk.Context, msg *evmtypes.EthereumTxMsg, priv tmcrypto.Pr
 	msg.Sign(chainID, privkey.ToECDSA())
 	return msg
 }
+
+// malleateSig returns the malleated counterpart (R, N-S, V^1) of a secp256k1
+// signature in [R || S || V] format.
+func malleateSig(sig []byte) []byte {
+	s := new(big.Int).Sub(ethcrypto.S256().Params().N, new(big.Int).SetBytes(sig[32:64]))
+
+	malleated := make([]byte, len(sig))
+	copy(malleated, sig[:32])
+	copy(malleated[64-len(s.Bytes()):64], s.Bytes())
+	malleated[64] = sig[64] ^ 1
+
+	return malleated
+}
@@ -0,0 +1,59 @@
+// Package debug implements the emintcli debug subcommands used to inspect
+// Ethermint data such as addresses.
+package debug
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/ethermint/types"
+
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the debug command and all of its subcommands.
+func Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Tool for helping with debugging your application",
+	}
+
+	cmd.AddCommand(addrCmd())
+
+	return cmd
+}
+
+func addrCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "addr <address>",
+		Short: "Convert an address between hex and bech32",
+		Long: `Convert an address between its Ethereum hex and bech32 representations.
+
+The address may be given as Ethereum hex (with or without the 0x prefix) or as
+a bech32 account or validator operator address.
+
+Example:
+$ emintcli debug addr 0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826
+$ emintcli debug addr emint1e54rm8un3cfum9r7cpdtcll8xn0cmkpxqm2hqu`,
+		Args: cobra.ExactArgs(1),
+		RunE: runAddrCmd,
+	}
+}
+
+func runAddrCmd(cmd *cobra.Command, args []string) error {
+	addr, err := types.ParseAddress(args[0])
+	if err != nil {
+		valAddr, valErr := sdk.ValAddressFromBech32(args[0])
+		if valErr != nil {
+			return err
+		}
+
+		addr = sdk.AccAddress(valAddr)
+	}
+
+	fmt.Println("Address (hex):", types.AccAddressToEthAddress(addr).Hex())
+	fmt.Println("Bech32 Acc:", addr.String())
+	fmt.Println("Bech32 Val:", sdk.ValAddress(addr).String())
+	return nil
+}
@@ -0,0 +1,108 @@
+package keys
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cosmos/cosmos-sdk/client"
+
+	"github.com/cosmos/ethermint/crypto/hd"
+	"github.com/cosmos/ethermint/crypto/keys"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	flagRecover = "recover"
+	flagHDPath  = "hd-path"
+)
+
+func addKeyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Create a new key, or import from a mnemonic",
+		Long: `Derive a new key from a newly generated BIP39 mnemonic and store it under the
+given name. The key is derived along the BIP44 HD path of the first Ethereum
+account, which matches the first account of MetaMask for the same mnemonic.
+
+Use --recover to restore a key from an existing mnemonic and --hd-path to
+derive the key at a different path, e.g. m/44'/60'/0'/0/1 for the second
+MetaMask account or m/44'/60'/1'/0/0 for the second Ledger Live account.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runAddCmd,
+	}
+
+	cmd.Flags().Bool(flagRecover, false, "Provide a mnemonic to recover an existing key")
+	cmd.Flags().String(flagHDPath, hd.BIP44HDPath, "BIP32 HD path to derive the key at")
+	return cmd
+}
+
+func runAddCmd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	hdPath := viper.GetString(flagHDPath)
+
+	if err := hd.ValidatePath(hdPath); err != nil {
+		return err
+	}
+
+	kb := GetKeybase()
+	defer kb.CloseDB()
+
+	if _, err := kb.Get(name); err == nil {
+		return fmt.Errorf("key %s already exists", name)
+	}
+
+	buf := client.BufferStdin()
+
+	passphrase, err := client.GetCheckPassword(
+		"Enter a passphrase to encrypt your key to disk:", "Repeat the passphrase:", buf,
+	)
+	if err != nil {
+		return err
+	}
+
+	var (
+		info     keys.Info
+		mnemonic string
+	)
+
+	if viper.GetBool(flagRecover) {
+		mnemonic, err = client.GetString("Enter your bip39 mnemonic", buf)
+		if err != nil {
+			return err
+		}
+
+		bip39Passphrase, err := client.GetString("Enter your bip39 passphrase (leave empty for none)", buf)
+		if err != nil {
+			return err
+		}
+
+		info, err = kb.Derive(name, mnemonic, bip39Passphrase, passphrase, hdPath)
+		if err != nil {
+			return err
+		}
+
+		return printInfo(info)
+	}
+
+	mnemonic, err = hd.NewMnemonic()
+	if err != nil {
+		return err
+	}
+
+	info, err = kb.Derive(name, mnemonic, "", passphrase, hdPath)
+	if err != nil {
+		return err
+	}
+
+	if err := printInfo(info); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, "\n**Important** write this mnemonic phrase in a safe place.")
+	fmt.Fprintln(os.Stderr, "It is the only way to recover your account if you ever forget your password.")
+	fmt.Fprintf(os.Stderr, "\n%s\n", mnemonic)
+
+	return nil
+}
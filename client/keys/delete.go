@@ -0,0 +1,42 @@
+package keys
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cosmos/cosmos-sdk/client"
+
+	"github.com/spf13/cobra"
+)
+
+func deleteKeyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete the given key",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runDeleteCmd,
+	}
+}
+
+func runDeleteCmd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	kb := GetKeybase()
+	defer kb.CloseDB()
+
+	if _, err := kb.Get(name); err != nil {
+		return err
+	}
+
+	passphrase, err := client.GetPassword("DANGER - enter passphrase to permanently delete key:", client.BufferStdin())
+	if err != nil {
+		return err
+	}
+
+	if err := kb.Delete(name, passphrase); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Key %s deleted\n", name)
+	return nil
+}
@@ -0,0 +1,65 @@
+package keys
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/cosmos/cosmos-sdk/client"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const flagOutput = "output"
+
+func exportKeystoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-keystore <name>",
+		Short: "Export a key as a geth compatible keystore file",
+		Long: `Export the key of the given name as a Web3 Secret Storage (keystore) JSON file
+which can be imported into geth or Parity. The key is encrypted with a new
+passphrase. The keystore is written to stdout unless an output file is given.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runExportKeystoreCmd,
+	}
+
+	cmd.Flags().StringP(flagOutput, "o", "", "File to write the keystore to")
+	return cmd
+}
+
+func runExportKeystoreCmd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	kb := GetKeybase()
+	defer kb.CloseDB()
+
+	if _, err := kb.Get(name); err != nil {
+		return err
+	}
+
+	buf := client.BufferStdin()
+
+	passphrase, err := client.GetPassword("Enter passphrase to decrypt your key:", buf)
+	if err != nil {
+		return err
+	}
+
+	exportPassphrase, err := client.GetCheckPassword(
+		"Enter a passphrase to encrypt the exported keystore:", "Repeat the passphrase:", buf,
+	)
+	if err != nil {
+		return err
+	}
+
+	keyJSON, err := kb.ExportKeystore(name, passphrase, exportPassphrase)
+	if err != nil {
+		return err
+	}
+
+	if output := viper.GetString(flagOutput); output != "" {
+		return ioutil.WriteFile(output, keyJSON, 0600)
+	}
+
+	fmt.Println(string(keyJSON))
+	return nil
+}
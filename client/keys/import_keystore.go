@@ -0,0 +1,60 @@
+package keys
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/cosmos/cosmos-sdk/client"
+
+	"github.com/spf13/cobra"
+)
+
+func importKeystoreCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import-keystore <name> <keyfile>",
+		Short: "Import a geth or Parity keystore file",
+		Long: `Import a Web3 Secret Storage (keystore) JSON file as written by geth or Parity
+under the given name. The key is re-encrypted with a new passphrase.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runImportKeystoreCmd,
+	}
+}
+
+func runImportKeystoreCmd(cmd *cobra.Command, args []string) error {
+	name, keyFile := args[0], args[1]
+
+	kb := GetKeybase()
+	defer kb.CloseDB()
+
+	if _, err := kb.Get(name); err == nil {
+		return fmt.Errorf("key %s already exists", name)
+	}
+
+	keyJSON, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return err
+	}
+
+	buf := client.BufferStdin()
+
+	passphrase, err := client.GetPassword("Enter passphrase to decrypt the keystore file:", buf)
+	if err != nil {
+		return err
+	}
+
+	encryptPassphrase, err := client.GetCheckPassword(
+		"Enter a passphrase to encrypt your key to disk:", "Repeat the passphrase:", buf,
+	)
+	if err != nil {
+		return err
+	}
+
+	info, err := kb.ImportKeystore(name, keyJSON, passphrase, encryptPassphrase)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Imported key %s: %s\n", name, info.Address.Hex())
+	return nil
+}
@@ -0,0 +1,67 @@
+// Package keys implements the emintcli keys subcommands used to manage
+// Ethermint's secp256k1 keys.
+package keys
+
+import (
+	"encoding/json"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/ethermint/crypto/keys"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tendermint/tendermint/libs/cli"
+)
+
+// Commands returns the keys command and all of its subcommands.
+func Commands() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Manage your application's keys",
+		Long: `Keys allows you to manage your local keybase for Ethermint.
+
+Keys are stored encrypted in the Web3 Secret Storage (keystore) format used by
+geth and Parity and are addressed by their Ethereum address.`,
+	}
+
+	cmd.AddCommand(
+		addKeyCmd(),
+		listKeysCmd(),
+		showKeyCmd(),
+		deleteKeyCmd(),
+		importKeystoreCmd(),
+		exportKeystoreCmd(),
+	)
+
+	return cmd
+}
+
+// GetKeybase returns the Keybase stored in the home directory.
+func GetKeybase() *keys.Keybase {
+	return keys.NewKeybaseFromDir(viper.GetString(cli.HomeFlag))
+}
+
+// keyOutput defines the key information printed by the keys commands. The
+// address is given both in Ethereum hex and bech32 format.
+type keyOutput struct {
+	keys.Info
+
+	Bech32Address sdk.AccAddress `json:"bech32_address"`
+}
+
+func newKeyOutput(info keys.Info) keyOutput {
+	return keyOutput{Info: info, Bech32Address: info.GetAccAddress()}
+}
+
+func printInfo(info keys.Info) error {
+	bz, err := json.MarshalIndent(newKeyOutput(info), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(bz))
+	return nil
+}
@@ -0,0 +1,40 @@
+package keys
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func listKeysCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List all keys",
+		Args:  cobra.NoArgs,
+		RunE:  runListCmd,
+	}
+}
+
+func runListCmd(cmd *cobra.Command, args []string) error {
+	kb := GetKeybase()
+	defer kb.CloseDB()
+
+	infos, err := kb.List()
+	if err != nil {
+		return err
+	}
+
+	outputs := make([]keyOutput, len(infos))
+	for i, info := range infos {
+		outputs[i] = newKeyOutput(info)
+	}
+
+	bz, err := json.MarshalIndent(outputs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(bz))
+	return nil
+}
@@ -0,0 +1,26 @@
+package keys
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func showKeyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show key info for the given name",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runShowCmd,
+	}
+}
+
+func runShowCmd(cmd *cobra.Command, args []string) error {
+	kb := GetKeybase()
+	defer kb.CloseDB()
+
+	info, err := kb.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	return printInfo(info)
+}
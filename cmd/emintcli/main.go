@@ -1,7 +1,39 @@
 package main
 
+import (
+	"os"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/ethermint/client/debug"
+	"github.com/cosmos/ethermint/client/keys"
+	"github.com/cosmos/ethermint/types"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tendermint/tendermint/libs/cli"
+)
+
 func main() {
-	// TODO: Implement CLI commands and logic
+	config := sdk.GetConfig()
+	types.SetBech32Prefixes(config)
+	config.Seal()
+
+	// TODO: Implement remaining CLI commands and logic
 	//
 	// Ref: https://github.com/cosmos/ethermint/issues/432
+	rootCmd := &cobra.Command{
+		Use:   "emintcli",
+		Short: "Ethermint Client",
+	}
+
+	rootCmd.AddCommand(
+		keys.Commands(),
+		debug.Cmd(),
+	)
+
+	executor := cli.PrepareMainCmd(rootCmd, "EM", os.ExpandEnv("$HOME/.emintcli"))
+	if err := executor.Execute(); err != nil {
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/cosmos/ethermint/app"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tendermint/tendermint/libs/cli"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	flagHeight = "height"
+	flagOutput = "output"
+)
+
+func dumpStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dump-state",
+		Short: "Dump the EVM state as Geth compatible JSON",
+		Long: `Dump all the accounts of the EVM state along with their balance, nonce,
+code and storage as JSON in the same format as 'geth dump'.
+
+The state of the latest committed block is dumped unless a height is given.
+The node must not be running.`,
+		Args: cobra.NoArgs,
+		RunE: runDumpStateCmd,
+	}
+
+	cmd.Flags().Int64(flagHeight, 0, "Height of the state to dump (defaults to the latest height)")
+	cmd.Flags().String(flagOutput, "", "File to write the dump to (defaults to stdout)")
+
+	return cmd
+}
+
+func runDumpStateCmd(cmd *cobra.Command, args []string) error {
+	dataDir := filepath.Join(viper.GetString(cli.HomeFlag), "data")
+
+	db, err := dbm.NewGoLevelDB("application", dataDir)
+	if err != nil {
+		return err
+	}
+
+	defer db.Close()
+
+	emintApp := app.NewEthermintApp(tmlog.NewNopLogger(), db, viper.GetUint(flagInvCheckPeriod))
+
+	height, err := cmd.Flags().GetInt64(flagHeight)
+	if err != nil {
+		return err
+	}
+
+	dump, err := emintApp.DumpState(height)
+	if err != nil {
+		return err
+	}
+
+	bz, err := json.MarshalIndent(dump, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	output, err := cmd.Flags().GetString(flagOutput)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		fmt.Println(string(bz))
+		return nil
+	}
+
+	return ioutil.WriteFile(output, bz, 0644)
+}
@@ -1,7 +1,42 @@
 package main
 
+import (
+	"os"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/ethermint/types"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tendermint/tendermint/libs/cli"
+)
+
+const flagInvCheckPeriod = "inv-check-period"
+
 func main() {
-	// TODO: Implement daemon command and logic
+	config := sdk.GetConfig()
+	types.SetBech32Prefixes(config)
+	config.Seal()
+
+	// TODO: Implement remaining daemon commands and logic
 	//
 	// Ref: https://github.com/cosmos/ethermint/issues/433
+	rootCmd := &cobra.Command{
+		Use:   "emintd",
+		Short: "Ethermint Daemon",
+	}
+
+	rootCmd.PersistentFlags().Uint(
+		flagInvCheckPeriod, 1, "Assert the application invariants every given number of blocks (zero disables them)",
+	)
+
+	rootCmd.AddCommand(
+		dumpStateCmd(),
+	)
+
+	executor := cli.PrepareBaseCmd(rootCmd, "EM", os.ExpandEnv("$HOME/.emintd"))
+	if err := executor.Execute(); err != nil {
+		os.Exit(1)
+	}
 }
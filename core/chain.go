@@ -3,15 +3,26 @@ package core
 // TODO: This functionality and implementation may be deprecated
 
 import (
+	"encoding/binary"
 	"math/big"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
 	ethcmn "github.com/ethereum/go-ethereum/common"
 	ethcons "github.com/ethereum/go-ethereum/consensus"
 	ethstate "github.com/ethereum/go-ethereum/core/state"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
 	ethrpc "github.com/ethereum/go-ethereum/rpc"
+
+	abci "github.com/tendermint/tendermint/abci/types"
 )
 
+// NumRecentHeaders defines the number of most recent block headers kept in the
+// header store. It matches the number of block hashes accessible through the
+// BLOCKHASH opcode.
+const NumRecentHeaders = 256
+
 // ChainContext implements Ethereum's core.ChainContext and consensus.Engine
 // interfaces. It is needed in order to apply and process Ethereum
 // transactions. There should only be a single implementation in Ethermint. For
@@ -19,16 +30,47 @@ import (
 // consensus parameters from  the current blockchain to be used during
 // transaction processing.
 //
+// Block headers are persisted in a KVStore keyed by block number so that they
+// survive restarts and the BLOCKHASH opcode is deterministic across nodes.
+//
 // NOTE: Ethermint will distribute the fees out to validators, so the structure
 // and functionality of this is a WIP and subject to change.
 type ChainContext struct {
-	Coinbase        ethcmn.Address
-	headersByNumber map[uint64]*ethtypes.Header
+	Coinbase ethcmn.Address
+
+	ctx       sdk.Context
+	headerKey sdk.StoreKey
 }
 
-func NewChainContext() *ChainContext {
+// NewChainContext returns a reference to a new ChainContext reading and
+// writing block headers in the store of the given key.
+func NewChainContext(ctx sdk.Context, headerKey sdk.StoreKey) *ChainContext {
 	return &ChainContext{
-		headersByNumber: make(map[uint64]*ethtypes.Header),
+		ctx:       ctx,
+		headerKey: headerKey,
+	}
+}
+
+// HeaderKey returns the header store key of the block header with the given
+// number. Numbers are big-endian encoded so headers are iterated in order.
+func HeaderKey(number uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, number)
+
+	return key
+}
+
+// NewEthHeader returns an Ethereum header for the given Tendermint block
+// header. The parent hash must be the hash of the Ethereum header of the
+// previous block in order for the headers to form a chain.
+func NewEthHeader(header abci.Header, parentHash ethcmn.Hash) *ethtypes.Header {
+	return &ethtypes.Header{
+		ParentHash: parentHash,
+		Root:       ethcmn.BytesToHash(header.AppHash),
+		TxHash:     ethcmn.BytesToHash(header.DataHash),
+		Difficulty: big.NewInt(0),
+		Number:     big.NewInt(header.Height),
+		Time:       big.NewInt(header.Time.Unix()),
 	}
 }
 
@@ -39,30 +81,58 @@ func (cc *ChainContext) Engine() ethcons.Engine {
 }
 
 // SetHeader implements Ethereum's core.ChainContext interface. It sets the
-// header for the given block number.
+// header for the given block number. Only the NumRecentHeaders most recent
+// headers are kept, so the header that falls out of range is removed.
 func (cc *ChainContext) SetHeader(number uint64, header *ethtypes.Header) {
-	cc.headersByNumber[number] = header
+	bz, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		panic(err)
+	}
+
+	store := cc.ctx.KVStore(cc.headerKey)
+	store.Set(HeaderKey(number), bz)
+
+	if number >= NumRecentHeaders {
+		store.Delete(HeaderKey(number - NumRecentHeaders))
+	}
 }
 
-// GetHeader implements Ethereum's core.ChainContext interface.
-//
-// TODO: The Cosmos SDK supports retreiving such information in contexts and
-// multi-store, so this will be need to be integrated.
-func (cc *ChainContext) GetHeader(_ ethcmn.Hash, number uint64) *ethtypes.Header {
-	if header, ok := cc.headersByNumber[number]; ok {
-		return header
+// GetHeader implements Ethereum's core.ChainContext interface. It returns the
+// header for the given block number if it is still kept in the header store
+// and its hash matches the given one. An empty hash matches any header.
+func (cc *ChainContext) GetHeader(hash ethcmn.Hash, number uint64) *ethtypes.Header {
+	bz := cc.ctx.KVStore(cc.headerKey).Get(HeaderKey(number))
+	if bz == nil {
+		return nil
 	}
 
-	return nil
+	header := new(ethtypes.Header)
+	if err := rlp.DecodeBytes(bz, header); err != nil {
+		panic(err)
+	}
+
+	if (hash != ethcmn.Hash{}) && header.Hash() != hash {
+		return nil
+	}
+
+	return header
 }
 
 // Author implements Ethereum's consensus.Engine interface. It is responsible
 // for returned the address of the validtor to receive any fees. This function
 // is only invoked if the given author in the ApplyTransaction call is nil.
 //
+// The coinbase of the given header is returned, which is the operator address
+// of the block proposer for headers set in the EVM BeginBlocker. The Coinbase
+// field is returned if the header has no coinbase.
+//
 // NOTE: Ethermint will distribute the fees out to validators, so the structure
 // and functionality of this is a WIP and subject to change.
-func (cc *ChainContext) Author(_ *ethtypes.Header) (ethcmn.Address, error) {
+func (cc *ChainContext) Author(header *ethtypes.Header) (ethcmn.Address, error) {
+	if header != nil && (header.Coinbase != ethcmn.Address{}) {
+		return header.Coinbase, nil
+	}
+
 	return cc.Coinbase, nil
 }
 
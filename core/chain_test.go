@@ -6,70 +6,117 @@ package core
 import (
 	"math/big"
 	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	ethcmn "github.com/ethereum/go-ethereum/common"
 	ethcons "github.com/ethereum/go-ethereum/consensus"
 	ethcore "github.com/ethereum/go-ethereum/core"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
 )
 
+func newTestChainContext(t *testing.T) *ChainContext {
+	db := dbm.NewMemDB()
+	headerKey := sdk.NewKVStoreKey("header")
+
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(headerKey, sdk.StoreTypeIAVL, db)
+	require.NoError(t, ms.LoadLatestVersion())
+
+	ctx := sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger())
+	return NewChainContext(ctx, headerKey)
+}
+
 func TestChainContextInterface(t *testing.T) {
 	require.Implements(t, (*ethcore.ChainContext)(nil), new(ChainContext))
 	require.Implements(t, (*ethcons.Engine)(nil), new(ChainContext))
 }
 
-func TestNewChainContext(t *testing.T) {
-	cc := NewChainContext()
-	require.NotNil(t, cc.headersByNumber)
-}
-
 func TestChainContextEngine(t *testing.T) {
-	cc := NewChainContext()
+	cc := newTestChainContext(t)
 	require.Equal(t, cc, cc.Engine())
 }
 
 func TestChainContextSetHeader(t *testing.T) {
-	cc := NewChainContext()
+	cc := newTestChainContext(t)
 	header := &ethtypes.Header{
 		Number: big.NewInt(64),
 	}
 
 	cc.SetHeader(uint64(header.Number.Int64()), header)
-	require.Equal(t, header, cc.headersByNumber[uint64(header.Number.Int64())])
+	require.NotNil(t, cc.ctx.KVStore(cc.headerKey).Get(HeaderKey(uint64(header.Number.Int64()))))
 }
 
 func TestChainContextGetHeader(t *testing.T) {
-	cc := NewChainContext()
-	header := &ethtypes.Header{
-		Number: big.NewInt(64),
-	}
+	cc := newTestChainContext(t)
+	header := NewEthHeader(abci.Header{Height: 64, Time: time.Unix(64, 0)}, ethcmn.Hash{})
 
 	cc.SetHeader(uint64(header.Number.Int64()), header)
-	require.Equal(t, header, cc.GetHeader(ethcmn.Hash{}, uint64(header.Number.Int64())))
+	require.Equal(t, header.Hash(), cc.GetHeader(ethcmn.Hash{}, uint64(header.Number.Int64())).Hash())
+	require.Equal(t, header.Hash(), cc.GetHeader(header.Hash(), uint64(header.Number.Int64())).Hash())
+	require.Nil(t, cc.GetHeader(ethcmn.BytesToHash([]byte{0x1}), uint64(header.Number.Int64())))
 	require.Nil(t, cc.GetHeader(ethcmn.Hash{}, 0))
 }
 
+func TestChainContextRecentHeaders(t *testing.T) {
+	cc := newTestChainContext(t)
+
+	var parentHash ethcmn.Hash
+	for height := int64(1); height <= NumRecentHeaders+10; height++ {
+		header := NewEthHeader(abci.Header{Height: height, Time: time.Unix(height, 0)}, parentHash)
+		cc.SetHeader(uint64(height), header)
+
+		parentHash = header.Hash()
+	}
+
+	// require only the most recent headers to be kept
+	require.Nil(t, cc.GetHeader(ethcmn.Hash{}, 10))
+	require.NotNil(t, cc.GetHeader(ethcmn.Hash{}, 11))
+	require.NotNil(t, cc.GetHeader(parentHash, NumRecentHeaders+10))
+
+	// require the headers to form a chain as walked by the BLOCKHASH opcode
+	ref := NewEthHeader(abci.Header{Height: NumRecentHeaders + 11}, parentHash)
+	getHash := ethcore.GetHashFn(ref, cc)
+
+	require.Equal(t, cc.GetHeader(ethcmn.Hash{}, 11).ParentHash, getHash(10))
+	require.Equal(t, cc.GetHeader(ethcmn.Hash{}, 100).Hash(), getHash(100))
+	require.Equal(t, ethcmn.Hash{}, getHash(9))
+}
+
 func TestChainContextAuthor(t *testing.T) {
-	cc := NewChainContext()
+	cc := newTestChainContext(t)
 
 	cb, err := cc.Author(nil)
 	require.Nil(t, err)
 	require.Equal(t, cc.Coinbase, cb)
+
+	// require the header coinbase to take precedence
+	header := &ethtypes.Header{Coinbase: ethcmn.BytesToAddress([]byte{0x1})}
+
+	cb, err = cc.Author(header)
+	require.Nil(t, err)
+	require.Equal(t, header.Coinbase, cb)
 }
 
 func TestChainContextAPIs(t *testing.T) {
-	cc := NewChainContext()
+	cc := newTestChainContext(t)
 	require.Nil(t, cc.APIs(nil))
 }
 
 func TestChainContextCalcDifficulty(t *testing.T) {
-	cc := NewChainContext()
+	cc := newTestChainContext(t)
 	require.Nil(t, cc.CalcDifficulty(nil, 0, nil))
 }
 
 func TestChainContextFinalize(t *testing.T) {
-	cc := NewChainContext()
+	cc := newTestChainContext(t)
 
 	block, err := cc.Finalize(nil, nil, nil, nil, nil, nil)
 	require.Nil(t, err)
@@ -77,28 +124,28 @@ func TestChainContextFinalize(t *testing.T) {
 }
 
 func TestChainContextPrepare(t *testing.T) {
-	cc := NewChainContext()
+	cc := newTestChainContext(t)
 
 	err := cc.Prepare(nil, nil)
 	require.Nil(t, err)
 }
 
 func TestChainContextSeal(t *testing.T) {
-	cc := NewChainContext()
+	cc := newTestChainContext(t)
 
 	err := cc.Seal(nil, nil, nil, nil)
 	require.Nil(t, err)
 }
 
 func TestChainContextVerifyHeader(t *testing.T) {
-	cc := NewChainContext()
+	cc := newTestChainContext(t)
 
 	err := cc.VerifyHeader(nil, nil, false)
 	require.Nil(t, err)
 }
 
 func TestChainContextVerifyHeaders(t *testing.T) {
-	cc := NewChainContext()
+	cc := newTestChainContext(t)
 
 	ch, err := cc.VerifyHeaders(nil, nil, []bool{false})
 	require.Nil(t, err)
@@ -106,14 +153,14 @@ func TestChainContextVerifyHeaders(t *testing.T) {
 }
 
 func TestChainContextVerifySeal(t *testing.T) {
-	cc := NewChainContext()
+	cc := newTestChainContext(t)
 
 	err := cc.VerifySeal(nil, nil)
 	require.Nil(t, err)
 }
 
 func TestChainContextVerifyUncles(t *testing.T) {
-	cc := NewChainContext()
+	cc := newTestChainContext(t)
 
 	err := cc.VerifyUncles(nil, nil)
 	require.Nil(t, err)
@@ -0,0 +1,49 @@
+package crypto
+
+import (
+	"fmt"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// textMsgPrefix defines the EIP-191 version 0x45 (E) prefix that is prepended
+// to messages signed through personal_sign and eth_sign.
+const textMsgPrefix = "\x19Ethereum Signed Message:\n"
+
+// TextHash returns the EIP-191 version 0x45 hash of the given message:
+//
+//	keccak256("\x19Ethereum Signed Message:\n" ‖ len(message) ‖ message)
+//
+// The prefix ensures a signed message can never be a valid transaction.
+//
+// Ref: https://eips.ethereum.org/EIPS/eip-191
+func TextHash(msg []byte) []byte {
+	prefix := fmt.Sprintf("%s%d", textMsgPrefix, len(msg))
+	return ethcrypto.Keccak256([]byte(prefix), msg)
+}
+
+// RecoverTextSigner returns the address of the account that created the given
+// signature over the EIP-191 hash of the message. The signature must be 65
+// bytes in [R || S || V] format where V is either 0/1 or 27/28.
+func RecoverTextSigner(msg, sig []byte) (ethcmn.Address, error) {
+	return recoverSigner(TextHash(msg), sig)
+}
+
+// SignText creates a recoverable ECDSA signature over the EIP-191 hash of the
+// given message as done by personal_sign. The produced signature is 65 bytes
+// where the last byte contains the recovery ID.
+func (privkey PrivKeySecp256k1) SignText(msg []byte) ([]byte, error) {
+	return ethcrypto.Sign(TextHash(msg), privkey.ToECDSA())
+}
+
+// VerifyText verifies that the ECDSA public key created a given signature over
+// the EIP-191 hash of the provided message.
+func (key PubKeySecp256k1) VerifyText(msg, sig []byte) bool {
+	signer, err := RecoverTextSigner(msg, sig)
+	if err != nil {
+		return false
+	}
+
+	return signer == ethcrypto.PubkeyToAddress(key.pubkey)
+}
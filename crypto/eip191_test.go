@@ -0,0 +1,51 @@
+package crypto
+
+import (
+	"fmt"
+	"testing"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextHash(t *testing.T) {
+	hash := TextHash([]byte("hello world"))
+	require.Equal(t, "D9EBA16ED0ECAE432B71FE008C98CC872BB4CC214D3220A36F365326CF807D68", fmt.Sprintf("%X", hash))
+}
+
+func TestPrivKeySecp256k1SignText(t *testing.T) {
+	privKey, err := GenerateKey()
+	require.NoError(t, err)
+
+	pubKey := privKey.PubKey().(PubKeySecp256k1)
+	msg := []byte("hello world")
+
+	sig, err := privKey.SignText(msg)
+	require.NoError(t, err)
+	require.Len(t, sig, 65)
+
+	// validate the signer can be recovered
+	signer, err := RecoverTextSigner(msg, sig)
+	require.NoError(t, err)
+	require.Equal(t, ethcrypto.PubkeyToAddress(privKey.PublicKey), signer)
+	require.True(t, pubKey.VerifyText(msg, sig))
+
+	// legacy V values of 27/28 are accepted
+	sig[64] += 27
+	require.True(t, pubKey.VerifyText(msg, sig))
+
+	// a prefixed signature is not valid over the raw message and vice versa
+	require.False(t, pubKey.VerifyBytes(msg, sig[:64]))
+
+	rawSig, err := privKey.Sign(msg)
+	require.NoError(t, err)
+	require.False(t, pubKey.VerifyText(msg, rawSig))
+
+	// validate the signature fails for a different message and key
+	require.False(t, pubKey.VerifyText([]byte("hello world!"), sig))
+
+	privKey2, err := GenerateKey()
+	require.NoError(t, err)
+	require.False(t, privKey2.PubKey().(PubKeySecp256k1).VerifyText(msg, sig))
+	require.False(t, pubKey.VerifyText(msg, sig[:64]))
+}
@@ -0,0 +1,439 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethmath "github.com/ethereum/go-ethereum/common/math"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// TypedDataDomainType defines the name of the EIP-712 domain separator struct
+// type. It must be defined in the types of every TypedData payload.
+const TypedDataDomainType = "EIP712Domain"
+
+type (
+	// TypedData defines an EIP-712 typed structured data payload as it is sent by
+	// wallets and dApps through eth_signTypedData.
+	//
+	// Ref: https://eips.ethereum.org/EIPS/eip-712
+	TypedData struct {
+		Types       TypedDataTypes         `json:"types"`
+		PrimaryType string                 `json:"primaryType"`
+		Domain      map[string]interface{} `json:"domain"`
+		Message     map[string]interface{} `json:"message"`
+	}
+
+	// TypedDataTypes maps a struct type name to its ordered list of members.
+	TypedDataTypes map[string][]TypedDataField
+
+	// TypedDataField defines a single named and typed member of a struct type.
+	TypedDataField struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+)
+
+// TypedDataHash returns the EIP-712 hash of the typed data that is signed over:
+//
+//	keccak256("\x19\x01" ‖ domainSeparator ‖ hashStruct(message))
+func TypedDataHash(td TypedData) (ethcmn.Hash, error) {
+	if err := td.ValidateBasic(); err != nil {
+		return ethcmn.Hash{}, err
+	}
+
+	domainSeparator, err := td.HashStruct(TypedDataDomainType, td.Domain)
+	if err != nil {
+		return ethcmn.Hash{}, fmt.Errorf("failed to hash domain: %s", err)
+	}
+
+	msgHash, err := td.HashStruct(td.PrimaryType, td.Message)
+	if err != nil {
+		return ethcmn.Hash{}, fmt.Errorf("failed to hash message: %s", err)
+	}
+
+	rawData := make([]byte, 0, 2+len(domainSeparator)+len(msgHash))
+	rawData = append(rawData, 0x19, 0x01)
+	rawData = append(rawData, domainSeparator...)
+	rawData = append(rawData, msgHash...)
+
+	return ethcrypto.Keccak256Hash(rawData), nil
+}
+
+// RecoverTypedDataSigner returns the address of the account that created the
+// given signature over the EIP-712 hash of the typed data. The signature must
+// be 65 bytes in [R || S || V] format where V is either 0/1 or 27/28.
+func RecoverTypedDataSigner(td TypedData, sig []byte) (ethcmn.Address, error) {
+	hash, err := TypedDataHash(td)
+	if err != nil {
+		return ethcmn.Address{}, err
+	}
+
+	return recoverSigner(hash.Bytes(), sig)
+}
+
+// SignTypedData creates a recoverable ECDSA signature over the EIP-712 hash of
+// the given typed data. The produced signature is 65 bytes where the last byte
+// contains the recovery ID.
+func (privkey PrivKeySecp256k1) SignTypedData(td TypedData) ([]byte, error) {
+	hash, err := TypedDataHash(td)
+	if err != nil {
+		return nil, err
+	}
+
+	return ethcrypto.Sign(hash.Bytes(), privkey.ToECDSA())
+}
+
+// ValidateBasic performs a stateless validation of the typed data's structure.
+func (td TypedData) ValidateBasic() error {
+	if _, ok := td.Types[TypedDataDomainType]; !ok {
+		return fmt.Errorf("typed data is missing the %s type", TypedDataDomainType)
+	}
+
+	if _, ok := td.Types[td.PrimaryType]; !ok {
+		return fmt.Errorf("primary type %q is not defined", td.PrimaryType)
+	}
+
+	for name, fields := range td.Types {
+		if name == "" || strings.ContainsAny(name, "()[], ") {
+			return fmt.Errorf("invalid type name: %q", name)
+		}
+
+		for _, field := range fields {
+			if field.Name == "" || field.Type == "" {
+				return fmt.Errorf("invalid field in type %s: %+v", name, field)
+			}
+		}
+	}
+
+	return nil
+}
+
+// HashStruct returns the EIP-712 hashStruct of the given data of a struct type:
+//
+//	keccak256(typeHash ‖ encodeData(data))
+func (td TypedData) HashStruct(primaryType string, data map[string]interface{}) ([]byte, error) {
+	encData, err := td.encodeData(primaryType, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return ethcrypto.Keccak256(td.TypeHash(primaryType), encData), nil
+}
+
+// TypeHash returns the Keccak256 hash of the encoded struct type.
+func (td TypedData) TypeHash(primaryType string) []byte {
+	return ethcrypto.Keccak256([]byte(td.EncodeType(primaryType)))
+}
+
+// EncodeType returns the EIP-712 encoding of a struct type. The encoding is the
+// primary type's signature followed by the signatures of all the struct types
+// it references, sorted by name, e.g.:
+//
+//	Mail(Person from,Person to,string contents)Person(string name,address wallet)
+func (td TypedData) EncodeType(primaryType string) string {
+	deps := td.dependencies(primaryType, nil)
+	if len(deps) == 0 {
+		return ""
+	}
+
+	// the primary type always comes first and the remaining types are sorted
+	sort.Strings(deps[1:])
+
+	var buf bytes.Buffer
+	for _, dep := range deps {
+		buf.WriteString(dep)
+		buf.WriteString("(")
+
+		for i, field := range td.Types[dep] {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+
+			buf.WriteString(field.Type)
+			buf.WriteString(" ")
+			buf.WriteString(field.Name)
+		}
+
+		buf.WriteString(")")
+	}
+
+	return buf.String()
+}
+
+// dependencies returns the given struct type followed by all the struct types
+// it (transitively) references.
+func (td TypedData) dependencies(primaryType string, found []string) []string {
+	primaryType = baseType(primaryType)
+
+	for _, dep := range found {
+		if dep == primaryType {
+			return found
+		}
+	}
+
+	if _, ok := td.Types[primaryType]; !ok {
+		return found
+	}
+
+	found = append(found, primaryType)
+	for _, field := range td.Types[primaryType] {
+		found = td.dependencies(field.Type, found)
+	}
+
+	return found
+}
+
+// encodeData returns the concatenation of the 32 byte encoded members of the
+// given struct type in the order of their definition.
+func (td TypedData) encodeData(primaryType string, data map[string]interface{}) ([]byte, error) {
+	fields, ok := td.Types[primaryType]
+	if !ok {
+		return nil, fmt.Errorf("type %q is not defined", primaryType)
+	}
+
+	if len(data) > len(fields) {
+		return nil, fmt.Errorf("data contains more members than type %s defines", primaryType)
+	}
+
+	var buf bytes.Buffer
+	for _, field := range fields {
+		value, ok := data[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing member %q of type %s", field.Name, primaryType)
+		}
+
+		encValue, err := td.encodeValue(field.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s.%s: %s", primaryType, field.Name, err)
+		}
+
+		buf.Write(encValue)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// encodeValue returns the 32 byte EIP-712 encoding of a single value of the
+// given type.
+func (td TypedData) encodeValue(typ string, value interface{}) ([]byte, error) {
+	// arrays are encoded as the hash of the concatenation of their encoded items
+	if strings.HasSuffix(typ, "]") {
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid array value: %v", value)
+		}
+
+		idx := strings.LastIndex(typ, "[")
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid array type: %s", typ)
+		}
+
+		if size := typ[idx+1 : len(typ)-1]; size != "" {
+			n, err := strconv.Atoi(size)
+			if err != nil || n != len(items) {
+				return nil, fmt.Errorf("invalid array length for type %s: %d", typ, len(items))
+			}
+		}
+
+		var buf bytes.Buffer
+		for _, item := range items {
+			encItem, err := td.encodeValue(typ[:idx], item)
+			if err != nil {
+				return nil, err
+			}
+
+			buf.Write(encItem)
+		}
+
+		return ethcrypto.Keccak256(buf.Bytes()), nil
+	}
+
+	// nested structs are encoded as their hashStruct
+	if _, ok := td.Types[typ]; ok {
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid struct value for type %s: %v", typ, value)
+		}
+
+		return td.HashStruct(typ, data)
+	}
+
+	switch {
+	case typ == "string":
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid string value: %v", value)
+		}
+
+		return ethcrypto.Keccak256([]byte(str)), nil
+
+	case typ == "bytes":
+		bz, err := parseTypedBytes(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return ethcrypto.Keccak256(bz), nil
+
+	case typ == "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid bool value: %v", value)
+		}
+
+		if b {
+			return ethmath.PaddedBigBytes(big.NewInt(1), 32), nil
+		}
+
+		return make([]byte, 32), nil
+
+	case typ == "address":
+		str, ok := value.(string)
+		if !ok || !ethcmn.IsHexAddress(str) {
+			return nil, fmt.Errorf("invalid address value: %v", value)
+		}
+
+		return ethcmn.LeftPadBytes(ethcmn.HexToAddress(str).Bytes(), 32), nil
+
+	case strings.HasPrefix(typ, "bytes"):
+		size, err := strconv.Atoi(strings.TrimPrefix(typ, "bytes"))
+		if err != nil || size < 1 || size > 32 {
+			return nil, fmt.Errorf("invalid type: %s", typ)
+		}
+
+		bz, err := parseTypedBytes(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(bz) > size {
+			return nil, fmt.Errorf("value too large for type %s: %d bytes", typ, len(bz))
+		}
+
+		return ethcmn.RightPadBytes(bz, 32), nil
+
+	case strings.HasPrefix(typ, "uint"), strings.HasPrefix(typ, "int"):
+		return encodeTypedInteger(typ, value)
+
+	default:
+		return nil, fmt.Errorf("unsupported type: %s", typ)
+	}
+}
+
+// encodeTypedInteger returns the 32 byte two's complement encoding of an
+// integer value of the given (u)intN type after validating it fits in N bits.
+func encodeTypedInteger(typ string, value interface{}) ([]byte, error) {
+	signed := strings.HasPrefix(typ, "int")
+
+	bits := 256
+	if size := strings.TrimPrefix(strings.TrimPrefix(typ, "u"), "int"); size != "" {
+		n, err := strconv.Atoi(size)
+		if err != nil || n < 8 || n > 256 || n%8 != 0 {
+			return nil, fmt.Errorf("invalid type: %s", typ)
+		}
+
+		bits = n
+	}
+
+	i, err := parseTypedInteger(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if signed {
+		limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+		if i.Cmp(new(big.Int).Neg(limit)) < 0 || i.Cmp(limit) >= 0 {
+			return nil, fmt.Errorf("value %s overflows type %s", i, typ)
+		}
+	} else if i.Sign() < 0 || i.BitLen() > bits {
+		return nil, fmt.Errorf("value %s overflows type %s", i, typ)
+	}
+
+	return ethmath.PaddedBigBytes(ethmath.U256(i), 32), nil
+}
+
+// parseTypedInteger parses an integer as it may be provided in the JSON
+// representation of typed data: a number, a decimal string or a hex string.
+func parseTypedInteger(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		return new(big.Int).Set(v), nil
+
+	case int:
+		return big.NewInt(int64(v)), nil
+
+	case int64:
+		return big.NewInt(v), nil
+
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+
+	case float64:
+		f := new(big.Float).SetFloat64(v)
+		if !f.IsInt() {
+			return nil, fmt.Errorf("invalid integer value: %v", v)
+		}
+
+		i, _ := f.Int(nil)
+		return i, nil
+
+	case json.Number:
+		return parseTypedInteger(string(v))
+
+	case string:
+		var (
+			i  *big.Int
+			ok bool
+		)
+
+		if strings.HasPrefix(v, "0x") || strings.HasPrefix(v, "0X") {
+			i, ok = new(big.Int).SetString(v[2:], 16)
+		} else {
+			i, ok = new(big.Int).SetString(v, 10)
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("invalid integer value: %s", v)
+		}
+
+		return i, nil
+
+	default:
+		return nil, fmt.Errorf("invalid integer value: %v", value)
+	}
+}
+
+// parseTypedBytes parses a byte slice as it may be provided in the JSON
+// representation of typed data, i.e. as a 0x prefixed hex string.
+func parseTypedBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+
+	case hexutil.Bytes:
+		return v, nil
+
+	case string:
+		return hexutil.Decode(v)
+
+	default:
+		return nil, fmt.Errorf("invalid bytes value: %v", value)
+	}
+}
+
+// baseType strips any array suffixes of a type, e.g. Person[][2] -> Person.
+func baseType(typ string) string {
+	if idx := strings.Index(typ, "["); idx >= 0 {
+		return typ[:idx]
+	}
+
+	return typ
+}
@@ -0,0 +1,180 @@
+package crypto
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// testTypedDataJSON is the example typed data payload of the EIP-712
+// specification.
+const testTypedDataJSON = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func newTestTypedData(t *testing.T) TypedData {
+	var td TypedData
+	require.NoError(t, json.Unmarshal([]byte(testTypedDataJSON), &td))
+
+	return td
+}
+
+func TestTypedDataEncodeType(t *testing.T) {
+	td := newTestTypedData(t)
+
+	require.Equal(t, "Mail(Person from,Person to,string contents)Person(string name,address wallet)", td.EncodeType("Mail"))
+	require.Equal(t, "Person(string name,address wallet)", td.EncodeType("Person"))
+	require.Equal(t, "", td.EncodeType("Unknown"))
+	require.Equal(t, "A0CEDEB2DC280BA39B857546D74F5549C3A1D7BDC2DD96BF881F76108E23DAC2", fmt.Sprintf("%X", td.TypeHash("Mail")))
+}
+
+func TestTypedDataHash(t *testing.T) {
+	td := newTestTypedData(t)
+
+	domainSeparator, err := td.HashStruct(TypedDataDomainType, td.Domain)
+	require.NoError(t, err)
+	require.Equal(t, "F2CEE375FA42B42143804025FC449DEAFD50CC031CA257E0B194A650A912090F", fmt.Sprintf("%X", domainSeparator))
+
+	msgHash, err := td.HashStruct(td.PrimaryType, td.Message)
+	require.NoError(t, err)
+	require.Equal(t, "C52C0EE5D84264471806290A3F2C4CECFC5490626BF912D01F240D7A274B371E", fmt.Sprintf("%X", msgHash))
+
+	hash, err := TypedDataHash(td)
+	require.NoError(t, err)
+	require.Equal(t, "BE609AEE343FB3C4B28E1DF9E632FCA64FCFAEDE20F02E86244EFDDF30957BD2", fmt.Sprintf("%X", hash))
+}
+
+func TestTypedDataValidation(t *testing.T) {
+	td := newTestTypedData(t)
+	td.PrimaryType = "Unknown"
+	_, err := TypedDataHash(td)
+	require.Error(t, err)
+
+	td = newTestTypedData(t)
+	delete(td.Types, TypedDataDomainType)
+	_, err = TypedDataHash(td)
+	require.Error(t, err)
+
+	// missing message member
+	td = newTestTypedData(t)
+	delete(td.Message, "contents")
+	_, err = TypedDataHash(td)
+	require.Error(t, err)
+
+	// invalid address member
+	td = newTestTypedData(t)
+	td.Message["to"] = map[string]interface{}{"name": "Bob", "wallet": "0xinvalid"}
+	_, err = TypedDataHash(td)
+	require.Error(t, err)
+}
+
+func TestTypedDataEncodeValue(t *testing.T) {
+	td := TypedData{Types: TypedDataTypes{}}
+
+	testCases := []struct {
+		typ        string
+		value      interface{}
+		expectPass bool
+	}{
+		{"uint8", float64(255), true},
+		{"uint8", float64(256), false},
+		{"uint256", "0xff", true},
+		{"uint256", "-1", false},
+		{"int8", float64(-128), true},
+		{"int8", float64(128), false},
+		{"int256", "-1", true},
+		{"uint", float64(1.5), false},
+		{"bool", true, true},
+		{"bool", "true", false},
+		{"bytes", "0x0102", true},
+		{"bytes2", "0x0102", true},
+		{"bytes2", "0x010203", false},
+		{"bytes33", "0x01", false},
+		{"string[]", []interface{}{"a", "b"}, true},
+		{"string[2]", []interface{}{"a"}, false},
+		{"Unknown", "a", false},
+	}
+
+	for i, tc := range testCases {
+		enc, err := td.encodeValue(tc.typ, tc.value)
+
+		if tc.expectPass {
+			require.NoError(t, err, "test: %v", i)
+			require.Len(t, enc, 32, "test: %v", i)
+		} else {
+			require.Error(t, err, "test: %v", i)
+		}
+	}
+
+	// negative integers are encoded in two's complement
+	enc, err := td.encodeValue("int256", "-1")
+	require.NoError(t, err)
+	require.Equal(t, ethcmn.Hex2Bytes("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"), enc)
+}
+
+func TestPrivKeySecp256k1SignTypedData(t *testing.T) {
+	td := newTestTypedData(t)
+
+	// the signer of the EIP-712 example is the key derived from keccak256("cow")
+	ecdsaKey, err := ethcrypto.ToECDSA(ethcrypto.Keccak256([]byte("cow")))
+	require.NoError(t, err)
+
+	privKey := PrivKeySecp256k1(*ecdsaKey)
+
+	sig, err := privKey.SignTypedData(td)
+	require.NoError(t, err)
+	require.Len(t, sig, 65)
+	require.Equal(t, "4355C47D63924E8A72E509B65029052EB6C299D53A04E167C5775FD466751C9D", fmt.Sprintf("%X", sig[:32]))
+	require.Equal(t, "07299936D304C153F6443DFA05F40FF007D72911B6F72307F996231605B91562", fmt.Sprintf("%X", sig[32:64]))
+
+	signer, err := RecoverTypedDataSigner(td, sig)
+	require.NoError(t, err)
+	require.Equal(t, ethcmn.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"), signer)
+
+	// legacy V values of 27/28 are accepted
+	sig[64] += 27
+	signer, err = RecoverTypedDataSigner(td, sig)
+	require.NoError(t, err)
+	require.Equal(t, ethcmn.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"), signer)
+
+	// the signature does not recover the signer for different data
+	td.Message["contents"] = "Hello, Alice!"
+	signer, err = RecoverTypedDataSigner(td, sig)
+	require.NoError(t, err)
+	require.NotEqual(t, ethcmn.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"), signer)
+
+	_, err = RecoverTypedDataSigner(td, sig[:64])
+	require.Error(t, err)
+}
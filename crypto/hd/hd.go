@@ -0,0 +1,93 @@
+// Package hd implements BIP39 mnemonic and BIP32/BIP44 hierarchical
+// deterministic key derivation for Ethermint's secp256k1 keys. Keys are derived
+// using the Ethereum coin type so that a mnemonic yields the same accounts as
+// in other Ethereum wallets such as MetaMask or Ledger Live.
+package hd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bartekn/go-bip39"
+	sdkhd "github.com/cosmos/cosmos-sdk/crypto/keys/hd"
+
+	"github.com/cosmos/ethermint/crypto"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// CoinType defines the SLIP-0044 registered coin type of Ethereum.
+	CoinType = 60
+
+	// BIP44HDPath defines the default BIP44 HD path of the first Ethereum
+	// account, i.e. m/purpose'/coin_type'/account'/change/address_index.
+	BIP44HDPath = "m/44'/60'/0'/0/0"
+
+	// mnemonicEntropySize defines the entropy size in bits of newly generated
+	// mnemonics (24 words).
+	mnemonicEntropySize = 256
+
+	hardenedOffset = 0x80000000
+)
+
+// NewMnemonic returns a new random 24 word BIP39 mnemonic.
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(mnemonicEntropySize)
+	if err != nil {
+		return "", err
+	}
+
+	return bip39.NewMnemonic(entropy)
+}
+
+// PathForIndex returns the default BIP44 HD path of the Ethereum account with
+// the given address index, i.e. m/44'/60'/0'/0/{index}.
+func PathForIndex(index uint32) string {
+	return fmt.Sprintf("m/44'/%d'/0'/0/%d", CoinType, index)
+}
+
+// ValidatePath validates that the given path is a well formed BIP32 HD path
+// such as m/44'/60'/0'/0/0.
+func ValidatePath(path string) error {
+	if !strings.HasPrefix(path, "m/") {
+		return fmt.Errorf("invalid HD path %q: must start with m/", path)
+	}
+
+	for _, part := range strings.Split(strings.TrimPrefix(path, "m/"), "/") {
+		idx, err := strconv.ParseUint(strings.TrimSuffix(part, "'"), 10, 32)
+		if err != nil || idx >= hardenedOffset {
+			return fmt.Errorf("invalid HD path %q: invalid index %q", path, part)
+		}
+	}
+
+	return nil
+}
+
+// DerivePrivKey derives the secp256k1 private key at the given BIP32 HD path
+// from a BIP39 mnemonic and an optional BIP39 passphrase.
+func DerivePrivKey(mnemonic, bip39Passphrase, path string) (crypto.PrivKeySecp256k1, error) {
+	if err := ValidatePath(path); err != nil {
+		return crypto.PrivKeySecp256k1{}, err
+	}
+
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, bip39Passphrase)
+	if err != nil {
+		return crypto.PrivKeySecp256k1{}, err
+	}
+
+	masterPriv, chainCode := sdkhd.ComputeMastersFromSeed(seed)
+
+	derivedKey, err := sdkhd.DerivePrivateKeyForPath(masterPriv, chainCode, strings.TrimPrefix(path, "m/"))
+	if err != nil {
+		return crypto.PrivKeySecp256k1{}, err
+	}
+
+	privKey, err := ethcrypto.ToECDSA(derivedKey[:])
+	if err != nil {
+		return crypto.PrivKeySecp256k1{}, err
+	}
+
+	return crypto.PrivKeySecp256k1(*privKey), nil
+}
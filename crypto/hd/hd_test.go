@@ -0,0 +1,76 @@
+package hd
+
+import (
+	"strings"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestDerivePrivKey(t *testing.T) {
+	testCases := []struct {
+		path         string
+		expectedAddr ethcmn.Address
+	}{
+		{BIP44HDPath, ethcmn.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")},
+		{PathForIndex(0), ethcmn.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")},
+		{PathForIndex(1), ethcmn.HexToAddress("0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0")},
+		// Ledger Live increments the account instead of the address index
+		{"m/44'/60'/1'/0/0", ethcmn.HexToAddress("0x78839f6054D7ed13918bAe0473Ba31b1cA9d7265")},
+	}
+
+	for i, tc := range testCases {
+		privKey, err := DerivePrivKey(testMnemonic, "", tc.path)
+		require.NoError(t, err, "test: %v", i)
+		require.Equal(t, tc.expectedAddr.Bytes(), privKey.PubKey().Address().Bytes(), "test: %v", i)
+	}
+
+	// a BIP39 passphrase results in a different key
+	privKey, err := DerivePrivKey(testMnemonic, "passphrase", BIP44HDPath)
+	require.NoError(t, err)
+	require.NotEqual(t, testCases[0].expectedAddr.Bytes(), privKey.PubKey().Address().Bytes())
+
+	// invalid mnemonics and paths fail
+	_, err = DerivePrivKey("abandon abandon", "", BIP44HDPath)
+	require.Error(t, err)
+
+	_, err = DerivePrivKey(testMnemonic, "", "44'/60'/0'/0/0")
+	require.Error(t, err)
+}
+
+func TestValidatePath(t *testing.T) {
+	testCases := []struct {
+		path       string
+		expectPass bool
+	}{
+		{BIP44HDPath, true},
+		{"m/44'/60'/0'/0", true},
+		{"m/0", true},
+		{"44'/60'/0'/0/0", false},
+		{"m/", false},
+		{"m/44'//0", false},
+		{"m/44'/-1", false},
+		{"m/44'/a/0", false},
+		{"m/2147483648", false},
+	}
+
+	for i, tc := range testCases {
+		if tc.expectPass {
+			require.NoError(t, ValidatePath(tc.path), "test: %v", i)
+		} else {
+			require.Error(t, ValidatePath(tc.path), "test: %v", i)
+		}
+	}
+}
+
+func TestNewMnemonic(t *testing.T) {
+	mnemonic, err := NewMnemonic()
+	require.NoError(t, err)
+	require.Len(t, strings.Fields(mnemonic), 24)
+
+	_, err = DerivePrivKey(mnemonic, "", BIP44HDPath)
+	require.NoError(t, err)
+}
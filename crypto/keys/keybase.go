@@ -0,0 +1,307 @@
+// Package keys implements a Keybase which stores and uses Ethermint's
+// secp256k1 keys. Keys are persisted on disk encrypted in the Web3 Secret
+// Storage (keystore) format and are addressed by their Ethereum address.
+package keys
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/crypto/hd"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+
+	"github.com/pkg/errors"
+
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+const (
+	// KeyDBName defines the name of the database holding the keys.
+	KeyDBName = "keys"
+
+	infoSuffix = "info"
+	addrPrefix = "addr"
+)
+
+// Keybase manages Ethermint secp256k1 keys persisted in a database. Private
+// keys are only ever stored encrypted with the passphrase given on creation.
+type Keybase struct {
+	db dbm.DB
+
+	// scrypt parameters used to encrypt private keys
+	scryptN int
+	scryptP int
+}
+
+// New returns a new Keybase persisting keys in the given database.
+func New(db dbm.DB) *Keybase {
+	return &Keybase{
+		db:      db,
+		scryptN: crypto.StandardScryptN,
+		scryptP: crypto.StandardScryptP,
+	}
+}
+
+// NewKeybaseFromDir returns a new Keybase persisting keys in a LevelDB database
+// in the keys directory of the given root directory.
+func NewKeybaseFromDir(rootDir string) *Keybase {
+	return New(dbm.NewDB(KeyDBName, dbm.GoLevelDBBackend, filepath.Join(rootDir, KeyDBName)))
+}
+
+// CreateMnemonic generates a new random BIP39 mnemonic and stores the key
+// derived from it at the default Ethereum BIP44 HD path under the given name.
+// The mnemonic is returned and must be kept by the user to restore the key.
+func (kb Keybase) CreateMnemonic(name, passphrase string) (Info, string, error) {
+	mnemonic, err := hd.NewMnemonic()
+	if err != nil {
+		return Info{}, "", err
+	}
+
+	info, err := kb.Derive(name, mnemonic, "", passphrase, hd.BIP44HDPath)
+	if err != nil {
+		return Info{}, "", err
+	}
+
+	return info, mnemonic, nil
+}
+
+// Derive derives the key at the given BIP32 HD path from a BIP39 mnemonic and
+// optional BIP39 passphrase and stores it under the given name encrypted with
+// encryptPassphrase.
+func (kb Keybase) Derive(name, mnemonic, bip39Passphrase, encryptPassphrase, hdPath string) (Info, error) {
+	privKey, err := hd.DerivePrivKey(mnemonic, bip39Passphrase, hdPath)
+	if err != nil {
+		return Info{}, err
+	}
+
+	return kb.Import(name, privKey, encryptPassphrase)
+}
+
+// Import stores the given private key under the given name encrypted with the
+// passphrase. A key cannot be stored under more than one name.
+func (kb Keybase) Import(name string, privKey crypto.PrivKeySecp256k1, passphrase string) (Info, error) {
+	if err := validateName(name); err != nil {
+		return Info{}, err
+	}
+
+	if kb.db.Has(infoKey(name)) {
+		return Info{}, fmt.Errorf("key %s already exists", name)
+	}
+
+	pubKey := privKey.PubKey().(crypto.PubKeySecp256k1)
+	info := Info{
+		Name:    name,
+		Address: ethcmn.BytesToAddress(pubKey.Address().Bytes()),
+		PubKey:  pubKey,
+	}
+
+	if existing := kb.db.Get(addrKey(info.GetAccAddress())); existing != nil {
+		return Info{}, fmt.Errorf("key with address %s already exists as %s", info.Address.Hex(), existing)
+	}
+
+	keyJSON, err := crypto.EncryptKeystore(privKey, passphrase, kb.scryptN, kb.scryptP)
+	if err != nil {
+		return Info{}, err
+	}
+
+	if err := kb.writeRecord(keyRecord{Info: info, Keystore: keyJSON}); err != nil {
+		return Info{}, err
+	}
+
+	return info, nil
+}
+
+// ImportKeystore decrypts a geth or Parity keystore file and stores the key
+// under the given name encrypted with encryptPassphrase.
+func (kb Keybase) ImportKeystore(name string, keyJSON []byte, passphrase, encryptPassphrase string) (Info, error) {
+	privKey, err := crypto.DecryptKeystore(keyJSON, passphrase)
+	if err != nil {
+		return Info{}, err
+	}
+
+	return kb.Import(name, privKey, encryptPassphrase)
+}
+
+// ExportKeystore returns the key of the given name as a geth compatible
+// keystore file encrypted with exportPassphrase.
+func (kb Keybase) ExportKeystore(name, passphrase, exportPassphrase string) ([]byte, error) {
+	privKey, err := kb.ExportPrivKey(name, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.EncryptKeystore(privKey, exportPassphrase, kb.scryptN, kb.scryptP)
+}
+
+// ExportPrivKey returns the decrypted private key of the given name.
+func (kb Keybase) ExportPrivKey(name, passphrase string) (crypto.PrivKeySecp256k1, error) {
+	record, err := kb.readRecord(name)
+	if err != nil {
+		return crypto.PrivKeySecp256k1{}, err
+	}
+
+	privKey, err := crypto.DecryptKeystore(record.Keystore, passphrase)
+	if err != nil {
+		return crypto.PrivKeySecp256k1{}, errors.Wrap(err, "invalid passphrase")
+	}
+
+	return privKey, nil
+}
+
+// List returns the information of all the stored keys sorted by name.
+func (kb Keybase) List() ([]Info, error) {
+	var infos []Info
+
+	iter := kb.db.Iterator(nil, nil)
+	defer iter.Close()
+
+	for ; iter.Valid(); iter.Next() {
+		if !strings.HasSuffix(string(iter.Key()), "."+infoSuffix) {
+			continue
+		}
+
+		var record keyRecord
+		if err := json.Unmarshal(iter.Value(), &record); err != nil {
+			return nil, err
+		}
+
+		infos = append(infos, record.Info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// Get returns the information of the key with the given name.
+func (kb Keybase) Get(name string) (Info, error) {
+	record, err := kb.readRecord(name)
+	if err != nil {
+		return Info{}, err
+	}
+
+	return record.Info, nil
+}
+
+// GetByAddress returns the information of the key with the given address.
+func (kb Keybase) GetByAddress(address sdk.AccAddress) (Info, error) {
+	name := kb.db.Get(addrKey(address))
+	if name == nil {
+		return Info{}, fmt.Errorf("key with address %s not found", ethcmn.BytesToAddress(address).Hex())
+	}
+
+	return kb.Get(string(name))
+}
+
+// Sign signs the message with the key of the given name the same way as
+// crypto.PrivKeySecp256k1 does. It returns the signature and the public key of
+// the signer.
+func (kb Keybase) Sign(name, passphrase string, msg []byte) ([]byte, tmcrypto.PubKey, error) {
+	privKey, err := kb.ExportPrivKey(name, passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sig, err := privKey.Sign(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sig, privKey.PubKey(), nil
+}
+
+// SignText signs the message with the key of the given name over its EIP-191
+// hash (see crypto.PrivKeySecp256k1.SignText). The key is decrypted for the
+// signature only and is not kept.
+func (kb Keybase) SignText(name, passphrase string, msg []byte) ([]byte, error) {
+	privKey, err := kb.ExportPrivKey(name, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return privKey.SignText(msg)
+}
+
+// SignTypedData signs the EIP-712 typed data with the key of the given name
+// (see crypto.PrivKeySecp256k1.SignTypedData). The key is decrypted for the
+// signature only and is not kept.
+func (kb Keybase) SignTypedData(name, passphrase string, td crypto.TypedData) ([]byte, error) {
+	privKey, err := kb.ExportPrivKey(name, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return privKey.SignTypedData(td)
+}
+
+// Delete removes the key of the given name. The passphrase must be able to
+// decrypt the key.
+func (kb Keybase) Delete(name, passphrase string) error {
+	record, err := kb.readRecord(name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := crypto.DecryptKeystore(record.Keystore, passphrase); err != nil {
+		return errors.Wrap(err, "invalid passphrase")
+	}
+
+	kb.db.DeleteSync(addrKey(record.Info.GetAccAddress()))
+	kb.db.DeleteSync(infoKey(name))
+
+	return nil
+}
+
+// CloseDB closes the database of the Keybase.
+func (kb Keybase) CloseDB() {
+	kb.db.Close()
+}
+
+func (kb Keybase) readRecord(name string) (keyRecord, error) {
+	bz := kb.db.Get(infoKey(name))
+	if bz == nil {
+		return keyRecord{}, fmt.Errorf("key %s not found", name)
+	}
+
+	var record keyRecord
+	if err := json.Unmarshal(bz, &record); err != nil {
+		return keyRecord{}, err
+	}
+
+	return record, nil
+}
+
+func (kb Keybase) writeRecord(record keyRecord) error {
+	bz, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	kb.db.SetSync(infoKey(record.Info.Name), bz)
+	kb.db.SetSync(addrKey(record.Info.GetAccAddress()), []byte(record.Info.Name))
+
+	return nil
+}
+
+func validateName(name string) error {
+	if name == "" || strings.ContainsAny(name, ".\n") {
+		return fmt.Errorf("invalid key name: %q", name)
+	}
+
+	return nil
+}
+
+func infoKey(name string) []byte {
+	return []byte(fmt.Sprintf("%s.%s", name, infoSuffix))
+}
+
+func addrKey(address sdk.AccAddress) []byte {
+	return []byte(fmt.Sprintf("%s.%X", addrPrefix, address.Bytes()))
+}
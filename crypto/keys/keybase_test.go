@@ -0,0 +1,174 @@
+package keys
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/crypto/hd"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func newTestKeybase() *Keybase {
+	kb := New(dbm.NewMemDB())
+	kb.scryptN = crypto.LightScryptN
+	kb.scryptP = crypto.LightScryptP
+
+	return kb
+}
+
+func TestKeybaseCreateAndList(t *testing.T) {
+	kb := newTestKeybase()
+
+	info, mnemonic, err := kb.CreateMnemonic("alice", "passphrase")
+	require.NoError(t, err)
+	require.Equal(t, "alice", info.Name)
+
+	// the mnemonic restores the same key
+	restored, err := hd.DerivePrivKey(mnemonic, "", hd.BIP44HDPath)
+	require.NoError(t, err)
+	require.Equal(t, info.Address.Bytes(), restored.PubKey().Address().Bytes())
+
+	// duplicate names are rejected
+	_, _, err = kb.CreateMnemonic("alice", "passphrase")
+	require.Error(t, err)
+
+	_, _, err = kb.CreateMnemonic("invalid.name", "passphrase")
+	require.Error(t, err)
+
+	info2, err := kb.Derive("bob", testMnemonic, "", "passphrase", hd.BIP44HDPath)
+	require.NoError(t, err)
+	require.Equal(t, ethcmn.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94"), info2.Address)
+
+	infos, err := kb.List()
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	require.Equal(t, "alice", infos[0].Name)
+	require.Equal(t, "bob", infos[1].Name)
+	require.True(t, info.PubKey.Equals(infos[0].PubKey))
+
+	res, err := kb.Get("bob")
+	require.NoError(t, err)
+	require.Equal(t, info2.Address, res.Address)
+
+	res, err = kb.GetByAddress(info2.GetAccAddress())
+	require.NoError(t, err)
+	require.Equal(t, "bob", res.Name)
+
+	_, err = kb.Get("carol")
+	require.Error(t, err)
+}
+
+func TestKeybaseSign(t *testing.T) {
+	kb := newTestKeybase()
+
+	info, _, err := kb.CreateMnemonic("alice", "passphrase")
+	require.NoError(t, err)
+
+	msg := []byte("hello world")
+	sig, pubKey, err := kb.Sign("alice", "passphrase", msg)
+	require.NoError(t, err)
+	require.True(t, info.PubKey.Equals(pubKey))
+	require.True(t, pubKey.VerifyBytes(msg, sig))
+
+	_, _, err = kb.Sign("alice", "wrong", msg)
+	require.Error(t, err)
+
+	_, _, err = kb.Sign("bob", "passphrase", msg)
+	require.Error(t, err)
+
+	sig, err = kb.SignText("alice", "passphrase", msg)
+	require.NoError(t, err)
+
+	signer, err := crypto.RecoverTextSigner(msg, sig)
+	require.NoError(t, err)
+	require.Equal(t, info.Address, signer)
+
+	_, err = kb.SignText("alice", "wrong", msg)
+	require.Error(t, err)
+}
+
+func TestKeybaseDelete(t *testing.T) {
+	kb := newTestKeybase()
+
+	info, _, err := kb.CreateMnemonic("alice", "passphrase")
+	require.NoError(t, err)
+
+	require.Error(t, kb.Delete("alice", "wrong"))
+	require.NoError(t, kb.Delete("alice", "passphrase"))
+
+	_, err = kb.Get("alice")
+	require.Error(t, err)
+
+	_, err = kb.GetByAddress(info.GetAccAddress())
+	require.Error(t, err)
+
+	infos, err := kb.List()
+	require.NoError(t, err)
+	require.Empty(t, infos)
+}
+
+func TestKeybaseImportExportKeystore(t *testing.T) {
+	kb := newTestKeybase()
+
+	privKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	keyJSON, err := crypto.EncryptKeystore(privKey, "geth", crypto.LightScryptN, crypto.LightScryptP)
+	require.NoError(t, err)
+
+	_, err = kb.ImportKeystore("alice", keyJSON, "wrong", "passphrase")
+	require.Error(t, err)
+
+	info, err := kb.ImportKeystore("alice", keyJSON, "geth", "passphrase")
+	require.NoError(t, err)
+	require.True(t, privKey.PubKey().Equals(info.PubKey))
+
+	exported, err := kb.ExportKeystore("alice", "passphrase", "export")
+	require.NoError(t, err)
+
+	privKey2, err := crypto.DecryptKeystore(exported, "export")
+	require.NoError(t, err)
+	require.True(t, privKey.Equals(privKey2))
+}
+
+func TestKeybaseImportDuplicate(t *testing.T) {
+	kb := newTestKeybase()
+
+	info, err := kb.Derive("alice", testMnemonic, "", "passphrase", hd.BIP44HDPath)
+	require.NoError(t, err)
+
+	// require the same key to be rejected under another name
+	_, err = kb.Derive("bob", testMnemonic, "", "passphrase", hd.BIP44HDPath)
+	require.Error(t, err)
+
+	_, err = kb.Get("bob")
+	require.Error(t, err)
+
+	res, err := kb.GetByAddress(info.GetAccAddress())
+	require.NoError(t, err)
+	require.Equal(t, "alice", res.Name)
+}
+
+func TestInfoJSON(t *testing.T) {
+	kb := newTestKeybase()
+
+	info, _, err := kb.CreateMnemonic("alice", "passphrase")
+	require.NoError(t, err)
+
+	bz, err := json.Marshal(info)
+	require.NoError(t, err)
+
+	var info2 Info
+	require.NoError(t, json.Unmarshal(bz, &info2))
+	require.Equal(t, info.Name, info2.Name)
+	require.Equal(t, info.Address, info2.Address)
+	require.True(t, info.PubKey.Equals(info2.PubKey))
+}
@@ -0,0 +1,30 @@
+package keys
+
+import (
+	"encoding/json"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/ethermint/crypto"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+)
+
+// Info defines the public information of a key stored in a Keybase.
+type Info struct {
+	Name    string                 `json:"name"`
+	Address ethcmn.Address         `json:"address"`
+	PubKey  crypto.PubKeySecp256k1 `json:"pubkey"`
+}
+
+// GetAccAddress returns the SDK account address of the key.
+func (i Info) GetAccAddress() sdk.AccAddress {
+	return sdk.AccAddress(i.Address.Bytes())
+}
+
+// keyRecord defines the record of a key persisted by a Keybase. The private key
+// is stored encrypted in the Web3 Secret Storage (keystore) format.
+type keyRecord struct {
+	Info     Info            `json:"info"`
+	Keystore json.RawMessage `json:"keystore"`
+}
@@ -0,0 +1,53 @@
+package crypto
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+)
+
+// Scrypt parameters used when encrypting a key into a keystore file. The
+// standard parameters match the ones used by geth and consume 256MB of memory
+// and about 1 second of CPU time. The light parameters consume 4MB of memory
+// and about 100ms of CPU time.
+const (
+	StandardScryptN = keystore.StandardScryptN
+	StandardScryptP = keystore.StandardScryptP
+
+	LightScryptN = keystore.LightScryptN
+	LightScryptP = keystore.LightScryptP
+)
+
+// DecryptKeystore decrypts a Web3 Secret Storage (keystore) JSON file as
+// written by geth or Parity into a secp256k1 private key. Both the scrypt and
+// pbkdf2 key derivation functions of version 3 files are supported.
+//
+// Ref: https://github.com/ethereum/wiki/wiki/Web3-Secret-Storage-Definition
+func DecryptKeystore(keyJSON []byte, passphrase string) (PrivKeySecp256k1, error) {
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return PrivKeySecp256k1{}, errors.Wrap(err, "failed to decrypt keystore")
+	}
+
+	return PrivKeySecp256k1(*key.PrivateKey), nil
+}
+
+// EncryptKeystore encrypts a secp256k1 private key into a version 3 Web3 Secret
+// Storage (keystore) JSON file using the scrypt key derivation function with
+// the given parameters. The result may be imported into geth or Parity.
+func EncryptKeystore(privkey PrivKeySecp256k1, passphrase string, scryptN, scryptP int) ([]byte, error) {
+	key := &keystore.Key{
+		Id:         uuid.NewRandom(),
+		Address:    ethcrypto.PubkeyToAddress(privkey.PublicKey),
+		PrivateKey: privkey.ToECDSA(),
+	}
+
+	keyJSON, err := keystore.EncryptKey(key, passphrase, scryptN, scryptP)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt keystore")
+	}
+
+	return keyJSON, nil
+}
@@ -0,0 +1,56 @@
+package crypto
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testKeystorePBKDF2 is the PBKDF2 test vector of the Web3 Secret Storage
+// definition. Its passphrase is "testpassword".
+const testKeystorePBKDF2 = `{
+	"crypto": {
+		"cipher": "aes-128-ctr",
+		"cipherparams": {"iv": "6087dab2f9fdbbfaddc31a909735c1e6"},
+		"ciphertext": "5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46",
+		"kdf": "pbkdf2",
+		"kdfparams": {
+			"c": 262144,
+			"dklen": 32,
+			"prf": "hmac-sha256",
+			"salt": "ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"
+		},
+		"mac": "517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"
+	},
+	"id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+	"version": 3
+}`
+
+func TestDecryptKeystore(t *testing.T) {
+	privKey, err := DecryptKeystore([]byte(testKeystorePBKDF2), "testpassword")
+	require.NoError(t, err)
+	require.Equal(t, "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d", fmt.Sprintf("%x", privKey.Bytes()))
+
+	_, err = DecryptKeystore([]byte(testKeystorePBKDF2), "wrongpassword")
+	require.Error(t, err)
+
+	_, err = DecryptKeystore([]byte("{}"), "testpassword")
+	require.Error(t, err)
+}
+
+func TestEncryptKeystore(t *testing.T) {
+	privKey, err := GenerateKey()
+	require.NoError(t, err)
+
+	keyJSON, err := EncryptKeystore(privKey, "passphrase", LightScryptN, LightScryptP)
+	require.NoError(t, err)
+
+	// validate the key round-trips
+	privKey2, err := DecryptKeystore(keyJSON, "passphrase")
+	require.NoError(t, err)
+	require.True(t, privKey.Equals(privKey2))
+
+	_, err = DecryptKeystore(keyJSON, "wrongpassword")
+	require.Error(t, err)
+}
@@ -3,9 +3,12 @@ package crypto
 import (
 	"bytes"
 	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
 
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	ethcrypto "github.com/ethereum/go-ethereum/crypto"
-	ethsecp256k1 "github.com/ethereum/go-ethereum/crypto/secp256k1"
 
 	tmcrypto "github.com/tendermint/tendermint/crypto"
 )
@@ -61,38 +64,94 @@ func (privkey PrivKeySecp256k1) ToECDSA() *ecdsa.PrivateKey {
 	return (*ecdsa.PrivateKey)(&privkey)
 }
 
+// MarshalAmino overrides Amino binary marshalling as the underlying ECDSA key
+// cannot be encoded by Amino. The key is encoded as its raw 32 bytes.
+func (privkey PrivKeySecp256k1) MarshalAmino() ([]byte, error) {
+	return privkey.Bytes(), nil
+}
+
+// UnmarshalAmino overrides Amino binary unmarshalling.
+func (privkey *PrivKeySecp256k1) UnmarshalAmino(bz []byte) error {
+	priv, err := ethcrypto.ToECDSA(bz)
+	if err != nil {
+		return err
+	}
+
+	*privkey = PrivKeySecp256k1(*priv)
+	return nil
+}
+
 // ----------------------------------------------------------------------------
 // secp256k1 Public Key
 
 var _ tmcrypto.PubKey = (*PubKeySecp256k1)(nil)
 
 // PubKeySecp256k1 defines a type alias for an ecdsa.PublicKey that implements
-// Tendermint's PubKey interface.
+// Tendermint's PubKey interface. Its canonical encoding, which is used by Bytes
+// and for Amino and JSON serialization, is the 33 byte compressed form.
 type PubKeySecp256k1 struct {
 	pubkey ecdsa.PublicKey
 }
 
+// PubKeySecp256k1Size defines the size of a compressed secp256k1 public key.
+const PubKeySecp256k1Size = 33
+
+// UnmarshalPubKey creates a PubKeySecp256k1 from either its 33 byte compressed
+// form as returned by Bytes or its 65 byte uncompressed form. It returns an
+// error if the bytes are not a valid public key.
+func UnmarshalPubKey(bz []byte) (PubKeySecp256k1, error) {
+	var (
+		pubkey *ecdsa.PublicKey
+		err    error
+	)
+
+	switch len(bz) {
+	case PubKeySecp256k1Size:
+		pubkey, err = ethcrypto.DecompressPubkey(bz)
+	default:
+		pubkey, err = ethcrypto.UnmarshalPubkey(bz)
+	}
+
+	if err != nil {
+		return PubKeySecp256k1{}, err
+	}
+
+	return PubKeySecp256k1{*pubkey}, nil
+}
+
 // Address returns the address of the ECDSA public key.
 func (key PubKeySecp256k1) Address() tmcrypto.Address {
 	return tmcrypto.Address(ethcrypto.PubkeyToAddress(key.pubkey).Bytes())
 }
 
-// Bytes returns the raw bytes of the ECDSA public key.
+// Bytes returns the 33 byte compressed form of the ECDSA public key. It returns
+// nil for an empty public key.
 func (key PubKeySecp256k1) Bytes() []byte {
+	if key.pubkey.X == nil || key.pubkey.Y == nil {
+		return nil
+	}
+
+	return ethcrypto.CompressPubkey(&key.pubkey)
+}
+
+// UncompressedBytes returns the 65 byte uncompressed form of the ECDSA public
+// key as used by Ethereum.
+func (key PubKeySecp256k1) UncompressedBytes() []byte {
 	return ethcrypto.FromECDSAPub(&key.pubkey)
 }
 
+// ToECDSA returns the ECDSA public key as a reference to ecdsa.PublicKey type.
+func (key PubKeySecp256k1) ToECDSA() *ecdsa.PublicKey {
+	pubkey := key.pubkey
+	return &pubkey
+}
+
 // VerifyBytes verifies that the ECDSA public key created a given signature over
 // the provided message. It will calculate the Keccak256 hash of the message
-// prior to verification.
+// prior to verification. The signature must be 65 bytes, including the recovery
+// ID, and must abide by the rules of ValidateSignature.
 func (key PubKeySecp256k1) VerifyBytes(msg []byte, sig []byte) bool {
-	if len(sig) == 65 {
-		// remove recovery ID if contained in the signature
-		sig = sig[:len(sig)-1]
-	}
-
-	// the signature needs to be in [R || S] format when provided to VerifySignature
-	return ethsecp256k1.VerifySignature(key.Bytes(), ethcrypto.Keccak256Hash(msg).Bytes(), sig)
+	return key.verifyHash(ethcrypto.Keccak256Hash(msg).Bytes(), sig)
 }
 
 // Equals returns true if two ECDSA public keys are equal and false otherwise.
@@ -103,3 +162,63 @@ func (key PubKeySecp256k1) Equals(other tmcrypto.PubKey) bool {
 
 	return false
 }
+
+// MarshalAmino overrides Amino binary marshalling as the underlying ECDSA key
+// cannot be encoded by Amino. The key is encoded in its compressed form.
+func (key PubKeySecp256k1) MarshalAmino() ([]byte, error) {
+	return key.Bytes(), nil
+}
+
+// UnmarshalAmino overrides Amino binary unmarshalling.
+func (key *PubKeySecp256k1) UnmarshalAmino(bz []byte) error {
+	pubKey, err := UnmarshalPubKey(bz)
+	if err != nil {
+		return err
+	}
+
+	*key = pubKey
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. The key is encoded as
+// the hex string of its compressed form.
+func (key PubKeySecp256k1) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hexutil.Bytes(key.Bytes()))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (key *PubKeySecp256k1) UnmarshalJSON(bz []byte) error {
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(bz, &raw); err != nil {
+		return err
+	}
+
+	return key.UnmarshalAmino(raw)
+}
+
+// ----------------------------------------------------------------------------
+// Signature Recovery
+
+// recoverSigner recovers the Ethereum address of the account that created the
+// given signature over a 32 byte hash. The signature must be 65 bytes in
+// [R || S || V] format where V is either 0/1 or 27/28 (legacy Ethereum).
+func recoverSigner(hash, sig []byte) (ethcmn.Address, error) {
+	if len(sig) != SignatureLength {
+		return ethcmn.Address{}, fmt.Errorf("invalid signature length: got %d, want %d", len(sig), SignatureLength)
+	}
+
+	// do not mutate the provided signature
+	sigCpy := make([]byte, len(sig))
+	copy(sigCpy, sig)
+
+	if sigCpy[64] >= 27 {
+		sigCpy[64] -= 27
+	}
+
+	pubKey, err := RecoverPubKey(hash, sigCpy)
+	if err != nil {
+		return ethcmn.Address{}, err
+	}
+
+	return ethcrypto.PubkeyToAddress(pubKey.pubkey), nil
+}
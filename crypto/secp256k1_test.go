@@ -1,9 +1,11 @@
 package crypto
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
+	"github.com/cosmos/cosmos-sdk/codec"
 	ethcrypto "github.com/ethereum/go-ethereum/crypto"
 	ethsecp256k1 "github.com/ethereum/go-ethereum/crypto/secp256k1"
 	"github.com/stretchr/testify/require"
@@ -59,3 +61,85 @@ func TestPrivKeySecp256k1PubKey(t *testing.T) {
 	res := pubKey.VerifyBytes(msg, sig)
 	require.True(t, res)
 }
+
+func TestUnmarshalPubKey(t *testing.T) {
+	privKey, err := GenerateKey()
+	require.NoError(t, err)
+
+	pubKey := privKey.PubKey().(PubKeySecp256k1)
+
+	pubKey2, err := UnmarshalPubKey(pubKey.Bytes())
+	require.NoError(t, err)
+	require.True(t, pubKey.Equals(pubKey2))
+
+	_, err = UnmarshalPubKey(pubKey.Bytes()[1:])
+	require.Error(t, err)
+}
+
+func TestPubKeySecp256k1Encoding(t *testing.T) {
+	privKey, err := GenerateKey()
+	require.NoError(t, err)
+
+	pubKey := privKey.PubKey().(PubKeySecp256k1)
+	require.Len(t, pubKey.Bytes(), PubKeySecp256k1Size)
+	require.Equal(t, ethcrypto.FromECDSAPub(&privKey.PublicKey), pubKey.UncompressedBytes())
+	require.Nil(t, PubKeySecp256k1{}.Bytes())
+
+	// validate the uncompressed form is accepted as well
+	pubKey2, err := UnmarshalPubKey(pubKey.UncompressedBytes())
+	require.NoError(t, err)
+	require.True(t, pubKey.Equals(pubKey2))
+
+	// validate JSON round-trips
+	bz, err := json.Marshal(pubKey)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("\"0x%x\"", pubKey.Bytes()), string(bz))
+
+	var pubKey3 PubKeySecp256k1
+	require.NoError(t, json.Unmarshal(bz, &pubKey3))
+	require.True(t, pubKey.Equals(pubKey3))
+	require.Equal(t, pubKey.Address(), pubKey3.Address())
+}
+
+func TestSecp256k1Amino(t *testing.T) {
+	cdc := codec.New()
+	codec.RegisterCrypto(cdc)
+	RegisterCodec(cdc)
+
+	privKey, err := GenerateKey()
+	require.NoError(t, err)
+
+	// validate the public key round-trips as an interface
+	var pubKey tmcrypto.PubKey = privKey.PubKey()
+
+	bz, err := cdc.MarshalBinaryBare(pubKey)
+	require.NoError(t, err)
+
+	var pubKey2 tmcrypto.PubKey
+	require.NoError(t, cdc.UnmarshalBinaryBare(bz, &pubKey2))
+	require.True(t, pubKey.Equals(pubKey2))
+
+	// validate the decoded key is able to verify signatures
+	msg := []byte("hello world")
+	sig, err := privKey.Sign(msg)
+	require.NoError(t, err)
+	require.True(t, pubKey2.VerifyBytes(msg, sig))
+
+	// validate the private key round-trips as an interface
+	var privKey2 tmcrypto.PrivKey = privKey
+
+	bz, err = cdc.MarshalBinaryBare(privKey2)
+	require.NoError(t, err)
+
+	var privKey3 tmcrypto.PrivKey
+	require.NoError(t, cdc.UnmarshalBinaryBare(bz, &privKey3))
+	require.True(t, privKey.Equals(privKey3))
+
+	// validate JSON round-trips
+	bz, err = cdc.MarshalJSON(pubKey)
+	require.NoError(t, err)
+
+	var pubKey4 tmcrypto.PubKey
+	require.NoError(t, cdc.UnmarshalJSON(bz, &pubKey4))
+	require.True(t, pubKey.Equals(pubKey4))
+}
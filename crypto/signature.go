@@ -0,0 +1,82 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	ethsecp256k1 "github.com/ethereum/go-ethereum/crypto/secp256k1"
+)
+
+// SignatureLength defines the canonical length of a secp256k1 signature in
+// [R || S || V] format where V is the recovery ID.
+const SignatureLength = 65
+
+var (
+	secp256k1N     = ethsecp256k1.S256().Params().N
+	secp256k1HalfN = new(big.Int).Div(secp256k1N, big.NewInt(2))
+)
+
+// ValidateSignature validates that a signature abides by the strict rules that
+// all secp256k1 signatures in Ethermint must follow, regardless of whether they
+// are part of an SDK or an Ethereum transaction:
+//
+// - the signature is exactly 65 bytes in [R || S || V] format
+// - R and S are within [1, N-1] where N is the order of the curve
+// - S is in the lower half of the curve order (low-S) to prevent malleability
+// - the recovery ID V is either 0 or 1
+func ValidateSignature(sig []byte) error {
+	if len(sig) != SignatureLength {
+		return fmt.Errorf("invalid signature length: got %d, want %d", len(sig), SignatureLength)
+	}
+
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+
+	if r.Sign() <= 0 || r.Cmp(secp256k1N) >= 0 {
+		return errors.New("invalid signature: R is out of range")
+	}
+
+	if s.Sign() <= 0 || s.Cmp(secp256k1N) >= 0 {
+		return errors.New("invalid signature: S is out of range")
+	}
+
+	if s.Cmp(secp256k1HalfN) > 0 {
+		return errors.New("invalid signature: S is not in the lower half of the curve order")
+	}
+
+	if v := sig[64]; v != 0 && v != 1 {
+		return fmt.Errorf("invalid signature: invalid recovery ID %d", v)
+	}
+
+	return nil
+}
+
+// RecoverPubKey recovers the public key that created the given signature over a
+// 32 byte hash. The signature must pass ValidateSignature.
+func RecoverPubKey(hash, sig []byte) (PubKeySecp256k1, error) {
+	if err := ValidateSignature(sig); err != nil {
+		return PubKeySecp256k1{}, err
+	}
+
+	pubkey, err := ethcrypto.SigToPub(hash, sig)
+	if err != nil {
+		return PubKeySecp256k1{}, err
+	}
+
+	return PubKeySecp256k1{*pubkey}, nil
+}
+
+// verifyHash verifies that the public key created the given signature over a
+// 32 byte hash. The signature must pass ValidateSignature and its recovery ID
+// must recover the public key itself.
+func (key PubKeySecp256k1) verifyHash(hash, sig []byte) bool {
+	recovered, err := RecoverPubKey(hash, sig)
+	if err != nil || !key.Equals(recovered) {
+		return false
+	}
+
+	// the signature needs to be in [R || S] format when provided to VerifySignature
+	return ethsecp256k1.VerifySignature(key.Bytes(), hash, sig[:SignatureLength-1])
+}
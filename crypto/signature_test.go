@@ -0,0 +1,154 @@
+package crypto
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	ethsecp256k1 "github.com/ethereum/go-ethereum/crypto/secp256k1"
+	"github.com/stretchr/testify/require"
+)
+
+// malleateSig returns the malleated counterpart (R, N-S, V^1) of a signature
+// which is an equally valid ECDSA signature over the same hash.
+func malleateSig(sig []byte) []byte {
+	s := new(big.Int).Sub(secp256k1N, new(big.Int).SetBytes(sig[32:64]))
+
+	malleated := make([]byte, len(sig))
+	copy(malleated, sig[:32])
+	copy(malleated[64-len(s.Bytes()):64], s.Bytes())
+	malleated[64] = sig[64] ^ 1
+
+	return malleated
+}
+
+func TestValidateSignature(t *testing.T) {
+	privKey, err := GenerateKey()
+	require.NoError(t, err)
+
+	sig, err := privKey.Sign([]byte("hello world"))
+	require.NoError(t, err)
+
+	withV := func(v byte) []byte {
+		cpy := append([]byte{}, sig...)
+		cpy[64] = v
+		return cpy
+	}
+
+	zeroR := append(make([]byte, 32), sig[32:]...)
+	zeroS := append(append(append([]byte{}, sig[:32]...), make([]byte, 32)...), sig[64])
+	overflowR := append(ethcrypto.S256().Params().N.Bytes(), sig[32:]...)
+
+	testCases := []struct {
+		name       string
+		sig        []byte
+		expectPass bool
+	}{
+		{"valid", sig, true},
+		{"valid, flipped recovery ID", withV(sig[64] ^ 1), true},
+		{"high S", malleateSig(sig), false},
+		{"missing recovery ID", sig[:64], false},
+		{"trailing byte", append(append([]byte{}, sig...), 0), false},
+		{"empty", nil, false},
+		{"zero R", zeroR, false},
+		{"zero S", zeroS, false},
+		{"R equal to N", overflowR, false},
+		{"recovery ID 2", withV(2), false},
+		{"legacy recovery ID 27", withV(27), false},
+	}
+
+	for _, tc := range testCases {
+		err := ValidateSignature(tc.sig)
+		if tc.expectPass {
+			require.NoError(t, err, tc.name)
+		} else {
+			require.Error(t, err, tc.name)
+		}
+	}
+}
+
+func TestPubKeySecp256k1VerifyBytesStrict(t *testing.T) {
+	privKey, err := GenerateKey()
+	require.NoError(t, err)
+
+	pubKey := privKey.PubKey()
+	msg := []byte("hello world")
+
+	sig, err := privKey.Sign(msg)
+	require.NoError(t, err)
+	require.True(t, pubKey.VerifyBytes(msg, sig))
+
+	// the malleated signature is a valid ECDSA signature but must be rejected
+	malleated := malleateSig(sig)
+	hash := ethcrypto.Keccak256(msg)
+	require.True(t, ethsecp256k1.VerifySignature(pubKey.Bytes(), hash, malleated[:64]))
+	require.False(t, pubKey.VerifyBytes(msg, malleated))
+	require.False(t, pubKey.(PubKeySecp256k1).VerifyText(msg, malleated))
+
+	// signatures without or with an inconsistent recovery ID must be rejected
+	require.False(t, pubKey.VerifyBytes(msg, sig[:64]))
+
+	flipped := append([]byte{}, sig...)
+	flipped[64] ^= 1
+	require.False(t, pubKey.VerifyBytes(msg, flipped))
+}
+
+// TestSignatureFuzz applies random mutations to valid signatures and feeds
+// random data to the signature verification and recovery functions. It
+// validates that they never panic and that all of them accept exactly the same
+// set of signatures.
+func TestSignatureFuzz(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		privKey, err := GenerateKey()
+		require.NoError(t, err)
+
+		pubKey := privKey.PubKey().(PubKeySecp256k1)
+
+		msg := make([]byte, r.Intn(128))
+		r.Read(msg)
+
+		sig, err := privKey.Sign(msg)
+		require.NoError(t, err)
+		require.NoError(t, ValidateSignature(sig))
+		require.True(t, pubKey.VerifyBytes(msg, sig))
+
+		// mutate a random number of random bytes of the signature
+		mutated := append([]byte{}, sig...)
+		for j := r.Intn(4); j >= 0; j-- {
+			mutated[r.Intn(len(mutated))] ^= byte(r.Intn(255) + 1)
+		}
+
+		requireConsistentVerification(t, pubKey, msg, mutated)
+		requireConsistentVerification(t, pubKey, msg, malleateSig(sig))
+
+		// random data of random length
+		random := make([]byte, r.Intn(2*SignatureLength))
+		r.Read(random)
+
+		requireConsistentVerification(t, pubKey, msg, random)
+	}
+}
+
+func requireConsistentVerification(t *testing.T, pubKey PubKeySecp256k1, msg, sig []byte) {
+	hash := ethcrypto.Keccak256(msg)
+
+	require.NotPanics(t, func() {
+		valid := pubKey.VerifyBytes(msg, sig)
+
+		recovered, err := RecoverPubKey(hash, sig)
+		require.Equal(t, valid, err == nil && pubKey.Equals(recovered))
+
+		if err := ValidateSignature(sig); err != nil {
+			require.False(t, valid)
+		}
+
+		if valid {
+			signer, err := recoverSigner(hash, sig)
+			require.NoError(t, err)
+			require.Equal(t, ethcrypto.PubkeyToAddress(pubKey.pubkey), signer)
+		}
+	})
+}
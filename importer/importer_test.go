@@ -17,6 +17,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/store"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/params"
 
 	"github.com/cosmos/ethermint/core"
 	"github.com/cosmos/ethermint/types"
@@ -51,6 +52,7 @@ var (
 	accKey     = sdk.NewKVStoreKey("acc")
 	storageKey = sdk.NewKVStoreKey("storage")
 	codeKey    = sdk.NewKVStoreKey("code")
+	headerKey  = sdk.NewKVStoreKey("header")
 
 	logger = tmlog.NewNopLogger()
 
@@ -97,12 +99,15 @@ func trapSignals() {
 	}()
 }
 
-func createAndTestGenesis(t *testing.T, cms sdk.CommitMultiStore, ak auth.AccountKeeper) {
+func createAndTestGenesis(
+	t *testing.T, cms sdk.CommitMultiStore, ak auth.AccountKeeper, paramSpace params.Subspace,
+) {
+
 	genBlock := ethcore.DefaultGenesisBlock()
 	ms := cms.CacheMultiStore()
 	ctx := sdk.NewContext(ms, abci.Header{}, false, logger)
 
-	stateDB, err := evmtypes.NewCommitStateDB(ctx, ak, storageKey, codeKey)
+	stateDB, err := evmtypes.NewCommitStateDB(ctx, ak, paramSpace, storageKey, codeKey)
 	require.NoError(t, err, "failed to create a StateDB instance")
 
 	// sort the addresses and insertion of key/value pairs matters
@@ -171,13 +176,17 @@ func TestImportBlocks(t *testing.T) {
 	cdc := newTestCodec()
 	cms := store.NewCommitMultiStore(db)
 	ak := auth.NewAccountKeeper(cdc, accKey, types.ProtoBaseAccount)
+	pk := params.NewKeeper(cdc, paramsKey, tParamsKey)
+	evmParamSpace := pk.Subspace(evmtypes.DefaultParamspace).WithTypeTable(evmtypes.ParamTypeTable())
 
 	// mount stores
-	keys := []*sdk.KVStoreKey{accKey, storageKey, codeKey}
+	keys := []*sdk.KVStoreKey{accKey, storageKey, codeKey, headerKey, paramsKey}
 	for _, key := range keys {
 		cms.MountStoreWithDB(key, sdk.StoreTypeIAVL, nil)
 	}
 
+	cms.MountStoreWithDB(tParamsKey, sdk.StoreTypeTransient, nil)
+
 	cms.SetPruning(sdk.PruneNothing)
 
 	// load latest version (root)
@@ -185,7 +194,7 @@ func TestImportBlocks(t *testing.T) {
 	require.NoError(t, err)
 
 	// set and test genesis block
-	createAndTestGenesis(t, cms, ak)
+	createAndTestGenesis(t, cms, ak, evmParamSpace)
 
 	// open blockchain export file
 	blockchainInput, err := os.Open(flagBlockchain)
@@ -194,7 +203,6 @@ func TestImportBlocks(t *testing.T) {
 	defer blockchainInput.Close()
 
 	// ethereum mainnet config
-	chainContext := core.NewChainContext()
 	vmConfig := ethvm.Config{}
 	chainConfig := ethparams.MainnetChainConfig
 
@@ -216,18 +224,19 @@ func TestImportBlocks(t *testing.T) {
 			gp      = new(ethcore.GasPool).AddGas(block.GasLimit())
 		)
 
-		header := block.Header()
-		chainContext.Coinbase = header.Coinbase
-
-		chainContext.SetHeader(block.NumberU64(), header)
-
 		// Create a cached-wrapped multi-store based on the commit multi-store and
 		// create a new context based off of that.
 		ms := cms.CacheMultiStore()
 		ctx := sdk.NewContext(ms, abci.Header{}, false, logger)
 		ctx = ctx.WithBlockHeight(int64(block.NumberU64()))
 
-		stateDB := createStateDB(t, ctx, ak)
+		header := block.Header()
+		chainContext := core.NewChainContext(ctx, headerKey)
+		chainContext.Coinbase = header.Coinbase
+
+		chainContext.SetHeader(block.NumberU64(), header)
+
+		stateDB := createStateDB(t, ctx, ak, evmParamSpace)
 
 		if chainConfig.DAOForkSupport && chainConfig.DAOForkBlock != nil && chainConfig.DAOForkBlock.Cmp(block.Number()) == 0 {
 			ethmisc.ApplyDAOHardFork(stateDB)
@@ -260,8 +269,11 @@ func TestImportBlocks(t *testing.T) {
 	}
 }
 
-func createStateDB(t *testing.T, ctx sdk.Context, ak auth.AccountKeeper) *evmtypes.CommitStateDB {
-	stateDB, err := evmtypes.NewCommitStateDB(ctx, ak, storageKey, codeKey)
+func createStateDB(
+	t *testing.T, ctx sdk.Context, ak auth.AccountKeeper, paramSpace params.Subspace,
+) *evmtypes.CommitStateDB {
+
+	stateDB, err := evmtypes.NewCommitStateDB(ctx, ak, paramSpace, storageKey, codeKey)
 	require.NoError(t, err, "failed to create a StateDB instance")
 
 	return stateDB
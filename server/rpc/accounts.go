@@ -0,0 +1,127 @@
+package rpc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/ethermint/crypto/keys"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// defaultUnlockDuration defines how long an account stays unlocked when no
+// duration is given, as in Geth.
+const defaultUnlockDuration = 300 * time.Second
+
+// AccountManager resolves the accounts managed by the node from a Keybase and
+// keeps track of the unlocked ones. Only the passphrases of unlocked accounts
+// are kept: private keys are decrypted by the Keybase for each signature.
+type AccountManager struct {
+	kb *keys.Keybase
+
+	mtx      sync.Mutex
+	unlocked map[common.Address]unlockedAccount
+}
+
+type unlockedAccount struct {
+	passphrase string
+	expiry     time.Time // zero if unlocked until locked explicitly
+}
+
+// NewAccountManager returns a new AccountManager of the keys of the given
+// Keybase. All the accounts are locked.
+func NewAccountManager(kb *keys.Keybase) *AccountManager {
+	return &AccountManager{
+		kb:       kb,
+		unlocked: make(map[common.Address]unlockedAccount),
+	}
+}
+
+// accounts returns the addresses of all the keys of the Keybase.
+func (am *AccountManager) accounts() ([]common.Address, error) {
+	infos, err := am.kb.List()
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]common.Address, len(infos))
+	for i, info := range infos {
+		addrs[i] = info.Address
+	}
+
+	return addrs, nil
+}
+
+// keyName returns the name of the key of the given address in the Keybase.
+func (am *AccountManager) keyName(address common.Address) (string, error) {
+	info, err := am.kb.GetByAddress(sdk.AccAddress(address.Bytes()))
+	if err != nil {
+		return "", fmt.Errorf("unknown account: %s", address.Hex())
+	}
+
+	return info.Name, nil
+}
+
+// unlock unlocks the account of the given address for the given duration, or
+// until it is locked if the duration is zero. The passphrase must decrypt the
+// key of the account.
+func (am *AccountManager) unlock(address common.Address, passphrase string, duration time.Duration) error {
+	name, err := am.keyName(address)
+	if err != nil {
+		return err
+	}
+
+	if _, err := am.kb.ExportPrivKey(name, passphrase); err != nil {
+		return err
+	}
+
+	account := unlockedAccount{passphrase: passphrase}
+	if duration != 0 {
+		account.expiry = time.Now().Add(duration)
+	}
+
+	am.mtx.Lock()
+	am.unlocked[address] = account
+	am.mtx.Unlock()
+
+	return nil
+}
+
+// lock locks the account of the given address. It returns whether the account
+// was unlocked.
+func (am *AccountManager) lock(address common.Address) bool {
+	am.mtx.Lock()
+	defer am.mtx.Unlock()
+
+	_, ok := am.unlocked[address]
+	delete(am.unlocked, address)
+
+	return ok
+}
+
+// unlockedKey returns the key name and passphrase of the given account if it
+// is unlocked.
+func (am *AccountManager) unlockedKey(address common.Address) (name, passphrase string, err error) {
+	name, err = am.keyName(address)
+	if err != nil {
+		return "", "", err
+	}
+
+	am.mtx.Lock()
+	defer am.mtx.Unlock()
+
+	account, ok := am.unlocked[address]
+	if ok && !account.expiry.IsZero() && time.Now().After(account.expiry) {
+		delete(am.unlocked, address)
+		ok = false
+	}
+
+	if !ok {
+		return "", "", fmt.Errorf("account is locked: %s", address.Hex())
+	}
+
+	return name, account.passphrase, nil
+}
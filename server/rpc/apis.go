@@ -3,15 +3,20 @@
 package rpc
 
 import (
+	"github.com/cosmos/ethermint/crypto/keys"
 	"github.com/cosmos/ethermint/version"
 	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/ethereum/go-ethereum/crypto"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // GetRPCAPIs returns the master list of public APIs for use with
-// StartHTTPEndpoint.
-func GetRPCAPIs() []rpc.API {
+// StartHTTPEndpoint. The keys of the given Keybase are the accounts managed by
+// the node. They are unlocked with personal_unlockAccount to sign with the eth_
+// APIs.
+func GetRPCAPIs(kb *keys.Keybase) []rpc.API {
+	am := NewAccountManager(kb)
+
 	return []rpc.API{
 		{
 			Namespace: "web3",
@@ -21,7 +26,12 @@ func GetRPCAPIs() []rpc.API {
 		{
 			Namespace: "eth",
 			Version:   "1.0",
-			Service:   NewPublicEthAPI(),
+			Service:   NewPublicEthAPI(am),
+		},
+		{
+			Namespace: "personal",
+			Version:   "1.0",
+			Service:   NewPersonalEthAPI(am),
 		},
 	}
 }
@@ -42,5 +52,5 @@ func (a *PublicWeb3API) ClientVersion() string {
 
 // Sha3 returns the keccak-256 hash of the passed-in input.
 func (a *PublicWeb3API) Sha3(input hexutil.Bytes) hexutil.Bytes {
-	return crypto.Keccak256(input)
+	return ethcrypto.Keccak256(input)
 }
@@ -2,26 +2,54 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/cosmos/ethermint/crypto/keys"
 	"github.com/cosmos/ethermint/version"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	dbm "github.com/tendermint/tendermint/libs/db"
 )
 
+const testPassphrase = "passphrase"
+
 type apisTestSuite struct {
 	suite.Suite
 	Stop context.CancelFunc
 	Port int
+	Kb   *keys.Keybase
+	Key  crypto.PrivKeySecp256k1
 }
 
 func (s *apisTestSuite) SetupSuite() {
-	stop, port, err := startAPIServer()
+	key, err := crypto.GenerateKey()
+	require.Nil(s.T(), err, "unexpected error")
+
+	kb := keys.New(dbm.NewMemDB())
+	_, err = kb.Import("test", key, testPassphrase)
+	require.Nil(s.T(), err, "unexpected error")
+
+	stop, port, err := startAPIServer(kb)
 	require.Nil(s.T(), err, "unexpected error")
 	s.Stop = stop
 	s.Port = port
+	s.Kb = kb
+	s.Key = key
+
+	res, err := rpcCall(s.Port, "personal_unlockAccount", []interface{}{s.address(), testPassphrase, 0})
+	require.Nil(s.T(), err, "unexpected error")
+	require.Equal(s.T(), true, res)
+}
+
+func (s *apisTestSuite) address() common.Address {
+	return common.BytesToAddress(s.Key.PubKey().Address().Bytes())
 }
 
 func (s *apisTestSuite) TearDownSuite() {
@@ -50,11 +78,96 @@ func (s *apisTestSuite) TestMiningAPIs() {
 	require.Equal(s.T(), "0x0", res)
 }
 
+func (s *apisTestSuite) TestPublicEthAPIAccounts() {
+	res, err := rpcCall(s.Port, "eth_accounts", nil)
+	require.Nil(s.T(), err, "unexpected error")
+	require.Contains(s.T(), res, strings.ToLower(s.address().Hex()))
+}
+
+func (s *apisTestSuite) TestPublicEthAPISignTypedData() {
+	var typedData crypto.TypedData
+	err := json.Unmarshal([]byte(`{
+		"types": {
+			"EIP712Domain": [{"name": "name", "type": "string"}, {"name": "chainId", "type": "uint256"}],
+			"Permit": [{"name": "spender", "type": "address"}, {"name": "value", "type": "uint256"}]
+		},
+		"primaryType": "Permit",
+		"domain": {"name": "Ethermint", "chainId": 3},
+		"message": {"spender": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB", "value": "0x64"}
+	}`), &typedData)
+	require.Nil(s.T(), err, "unexpected error")
+
+	res, err := rpcCall(s.Port, "eth_signTypedData", []interface{}{s.address(), typedData})
+	require.Nil(s.T(), err, "unexpected error")
+
+	sig, err := hexutil.Decode(res.(string))
+	require.Nil(s.T(), err, "unexpected error")
+	require.Len(s.T(), sig, 65)
+	require.True(s.T(), sig[64] == 27 || sig[64] == 28)
+
+	signer, err := crypto.RecoverTypedDataSigner(typedData, sig)
+	require.Nil(s.T(), err, "unexpected error")
+	require.Equal(s.T(), s.address(), signer)
+}
+
+func (s *apisTestSuite) TestPublicEthAPISign() {
+	res, err := rpcCall(s.Port, "eth_sign", []string{s.address().Hex(), "0x68656c6c6f20776f726c64"})
+	require.Nil(s.T(), err, "unexpected error")
+
+	sig, err := hexutil.Decode(res.(string))
+	require.Nil(s.T(), err, "unexpected error")
+	require.Len(s.T(), sig, 65)
+	require.True(s.T(), sig[64] == 27 || sig[64] == 28)
+
+	res, err = rpcCall(s.Port, "personal_ecRecover", []string{"0x68656c6c6f20776f726c64", hexutil.Encode(sig)})
+	require.Nil(s.T(), err, "unexpected error")
+	require.Equal(s.T(), strings.ToLower(s.address().Hex()), res)
+}
+
+func (s *apisTestSuite) TestPersonalAPISignLocked() {
+	key, err := crypto.GenerateKey()
+	require.Nil(s.T(), err, "unexpected error")
+
+	_, err = s.Kb.Import("locked", key, testPassphrase)
+	require.Nil(s.T(), err, "unexpected error")
+
+	address := common.BytesToAddress(key.PubKey().Address().Bytes())
+
+	// a locked account cannot sign through the eth_ APIs
+	res, err := rpcCall(s.Port, "eth_sign", []string{address.Hex(), "0x68656c6c6f20776f726c64"})
+	require.Nil(s.T(), err, "unexpected error")
+	require.Nil(s.T(), res)
+
+	// but can sign given its passphrase
+	res, err = rpcCall(s.Port, "personal_sign", []string{"0x68656c6c6f20776f726c64", address.Hex(), "wrong"})
+	require.Nil(s.T(), err, "unexpected error")
+	require.Nil(s.T(), res)
+
+	res, err = rpcCall(s.Port, "personal_sign", []string{"0x68656c6c6f20776f726c64", address.Hex(), testPassphrase})
+	require.Nil(s.T(), err, "unexpected error")
+
+	sig, err := hexutil.Decode(res.(string))
+	require.Nil(s.T(), err, "unexpected error")
+
+	signer, err := crypto.RecoverTextSigner([]byte("hello world"), sig)
+	require.Nil(s.T(), err, "unexpected error")
+	require.Equal(s.T(), address, signer)
+
+	// an account cannot be unlocked with a wrong passphrase
+	res, err = rpcCall(s.Port, "personal_unlockAccount", []interface{}{address, "wrong", 0})
+	require.Nil(s.T(), err, "unexpected error")
+	require.Nil(s.T(), res)
+
+	res, err = rpcCall(s.Port, "personal_lockAccount", []interface{}{address})
+	require.Nil(s.T(), err, "unexpected error")
+	require.Equal(s.T(), false, res)
+}
+
 func TestAPIsTestSuite(t *testing.T) {
 	suite.Run(t, new(apisTestSuite))
 }
 
-func startAPIServer() (context.CancelFunc, int, error) {
+func startAPIServer(kb *keys.Keybase) (context.CancelFunc, int, error) {
 	config := &Config{
 		RPCAddr: "127.0.0.1",
 		RPCPort: randomPort(),
@@ -67,7 +180,7 @@ func startAPIServer() (context.CancelFunc, int, error) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 
-	_, err := StartHTTPEndpoint(ctx, config, GetRPCAPIs(), timeouts)
+	_, err := StartHTTPEndpoint(ctx, config, GetRPCAPIs(kb), timeouts)
 	if err != nil {
 		return cancel, 0, err
 	}
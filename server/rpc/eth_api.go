@@ -1,20 +1,26 @@
 package rpc
 
 import (
+	"math/big"
+
+	"github.com/cosmos/ethermint/crypto"
 	"github.com/cosmos/ethermint/version"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/signer/core"
-	"math/big"
 )
 
 // PublicEthAPI is the eth_ prefixed set of APIs in the Web3 JSON-RPC spec.
-type PublicEthAPI struct{}
+type PublicEthAPI struct {
+	am *AccountManager
+}
 
-// NewPublicEthAPI creates an instance of the public ETH Web3 API.
-func NewPublicEthAPI() *PublicEthAPI {
-	return &PublicEthAPI{}
+// NewPublicEthAPI creates an instance of the public ETH Web3 API. The accounts
+// of the given AccountManager are the accounts managed by the node, which sign
+// once unlocked.
+func NewPublicEthAPI(am *AccountManager) *PublicEthAPI {
+	return &PublicEthAPI{am: am}
 }
 
 // ProtocolVersion returns the supported Ethereum protocol version.
@@ -50,8 +56,8 @@ func (e *PublicEthAPI) GasPrice() *hexutil.Big {
 }
 
 // Accounts returns the list of accounts available to this node.
-func (e *PublicEthAPI) Accounts() []common.Address {
-	return nil
+func (e *PublicEthAPI) Accounts() ([]common.Address, error) {
+	return e.am.accounts()
 }
 
 // BlockNumber returns the current block number.
@@ -101,8 +107,41 @@ func (e *PublicEthAPI) GetCode(address common.Address, blockNumber rpc.BlockNumb
 }
 
 // Sign signs the provided data using the private key of address via Geth's signature standard.
-func (e *PublicEthAPI) Sign(address common.Address, data hexutil.Bytes) hexutil.Bytes {
-	return nil
+// The data is prefixed according to EIP-191 prior to signing and the returned
+// signature has a V value of 27 or 28. The account must be unlocked.
+func (e *PublicEthAPI) Sign(address common.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
+	name, passphrase, err := e.am.unlockedKey(address)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := e.am.kb.SignText(name, passphrase, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// transform V from 0/1 to 27/28 according to the Ethereum yellow paper
+	sig[64] += 27
+	return sig, nil
+}
+
+// SignTypedData signs EIP-712 conformant typed data using the private key of
+// address. The returned signature has a V value of 27 or 28. The account must be
+// unlocked.
+func (e *PublicEthAPI) SignTypedData(address common.Address, typedData crypto.TypedData) (hexutil.Bytes, error) {
+	name, passphrase, err := e.am.unlockedKey(address)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := e.am.kb.SignTypedData(name, passphrase, typedData)
+	if err != nil {
+		return nil, err
+	}
+
+	// transform V from 0/1 to 27/28 according to the Ethereum yellow paper
+	sig[64] += 27
+	return sig, nil
 }
 
 // SendTransaction sends an Ethereum transaction.
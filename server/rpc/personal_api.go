@@ -0,0 +1,83 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/cosmos/ethermint/crypto"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// PersonalEthAPI is the personal_ prefixed set of APIs in the Web3 JSON-RPC spec.
+type PersonalEthAPI struct {
+	am *AccountManager
+}
+
+// NewPersonalEthAPI creates an instance of the personal Web3 API. It unlocks
+// and signs with the accounts of the given AccountManager.
+func NewPersonalEthAPI(am *AccountManager) *PersonalEthAPI {
+	return &PersonalEthAPI{am: am}
+}
+
+// UnlockAccount unlocks the account of the given address for signing with the
+// eth_ APIs for the given number of seconds, five minutes by default. A
+// duration of zero unlocks the account until it is locked.
+func (e *PersonalEthAPI) UnlockAccount(address common.Address, passphrase string, duration *uint64) (bool, error) {
+	d := defaultUnlockDuration
+	if duration != nil {
+		d = time.Duration(*duration) * time.Second
+	}
+
+	if err := e.am.unlock(address, passphrase, d); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// LockAccount locks the account of the given address. It returns whether the
+// account was unlocked.
+func (e *PersonalEthAPI) LockAccount(address common.Address) bool {
+	return e.am.lock(address)
+}
+
+// Sign signs the provided data with the account of the given address, which
+// is decrypted with the passphrase for this signature only. The data is
+// prefixed according to EIP-191 prior to signing and the returned signature has
+// a V value of 27 or 28.
+func (e *PersonalEthAPI) Sign(data hexutil.Bytes, address common.Address, passphrase string) (hexutil.Bytes, error) {
+	name, err := e.am.keyName(address)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := e.am.kb.SignText(name, passphrase, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// transform V from 0/1 to 27/28 according to the Ethereum yellow paper
+	sig[64] += 27
+	return sig, nil
+}
+
+// EcRecover returns the address for the account that was used to create the
+// signature via personal_sign or eth_sign. The data is prefixed according to
+// EIP-191 prior to recovery. The signature must have a V value of 27 or 28.
+func (e *PersonalEthAPI) EcRecover(data, sig hexutil.Bytes) (common.Address, error) {
+	if len(sig) != 65 {
+		return common.Address{}, errors.New("signature must be 65 bytes long")
+	}
+
+	if sig[64] != 27 && sig[64] != 28 {
+		return common.Address{}, errors.New("invalid Ethereum signature (V is not 27 or 28)")
+	}
+
+	signer, err := crypto.RecoverTextSigner(data, sig)
+	if err != nil {
+		return common.Address{}, errors.Wrap(err, "failed to recover signer")
+	}
+
+	return signer, nil
+}
@@ -58,7 +58,7 @@ func TestStartHTTPEndpointStartStop(t *testing.T) {
 	require.NotNil(t, err)
 }
 
-func rpcCall(port int, method string, params []string) (interface{}, error) {
+func rpcCall(port int, method string, params interface{}) (interface{}, error) {
 	parsedParams, err := json.Marshal(params)
 	if err != nil {
 		return nil, err
@@ -1,19 +1,23 @@
 package types
 
 import (
+	"encoding/json"
 	"fmt"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 
+	"github.com/cosmos/ethermint/crypto"
+
 	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 var _ auth.Account = (*Account)(nil)
 
 const (
-	// DenomDefault defines the single coin type/denomination supported in
-	// Ethermint.
+	// DenomDefault defines the default coin denomination used by the EVM. The
+	// denomination in use is set by the EVM module parameters.
 	DenomDefault = "Photon"
 )
 
@@ -40,14 +44,155 @@ func ProtoBaseAccount() auth.Account {
 	return &Account{BaseAccount: &auth.BaseAccount{}}
 }
 
-// Balance returns the balance of an account.
-func (acc Account) Balance() sdk.Int {
-	return acc.GetCoins().AmountOf(DenomDefault)
+// Copy returns a deep copy of the account. Changes made to the copy, including
+// its coins, are not reflected on the original account and vice versa.
+func (acc *Account) Copy() *Account {
+	cpy := &Account{Root: acc.Root}
+
+	if acc.BaseAccount != nil {
+		baseAcc := *acc.BaseAccount
+		baseAcc.Address = append(sdk.AccAddress(nil), acc.Address...)
+		baseAcc.Coins = append(sdk.Coins(nil), acc.Coins...)
+
+		cpy.BaseAccount = &baseAcc
+	}
+
+	if acc.CodeHash != nil {
+		cpy.CodeHash = append([]byte{}, acc.CodeHash...)
+	}
+
+	return cpy
+}
+
+// Balance returns the balance of an account for the given denomination.
+func (acc *Account) Balance(denom string) sdk.Int {
+	return acc.GetCoins().AmountOf(denom)
+}
+
+// SetBalance sets an account's balance for the given denomination. The balances
+// of all other denominations held by the account are left untouched.
+func (acc *Account) SetBalance(denom string, amt sdk.Int) {
+	coins := sdk.Coins{}
+	for _, coin := range acc.GetCoins() {
+		if coin.Denom != denom {
+			coins = append(coins, coin)
+		}
+	}
+
+	if !amt.IsZero() {
+		coins = append(coins, sdk.NewCoin(denom, amt))
+	}
+
+	acc.SetCoins(coins.Sort())
+}
+
+// accountJSON defines the JSON representation of an Account suited to Ethereum
+// users. The address is given both in bech32 and Ethereum hex format, the code
+// hash in hex and the sequence as the account nonce. The balance is the amount
+// of the default EVM denomination (Photon) held by the account; accounts have
+// no access to the EVM module parameters, so the coins must be used if the
+// EVM denomination has been changed.
+type accountJSON struct {
+	Address       sdk.AccAddress          `json:"address"`
+	EthAddress    ethcmn.Address          `json:"eth_address"`
+	Coins         sdk.Coins               `json:"coins"`
+	Balance       *sdk.Int                `json:"balance,omitempty"`
+	PubKey        *crypto.PubKeySecp256k1 `json:"public_key"`
+	AccountNumber uint64                  `json:"account_number"`
+	Nonce         uint64                  `json:"nonce"`
+	Root          ethcmn.Hash             `json:"root"`
+	CodeHash      hexutil.Bytes           `json:"code_hash"`
+}
+
+// MarshalJSON returns the JSON representation of an Account.
+func (acc Account) MarshalJSON() ([]byte, error) {
+	if acc.BaseAccount == nil {
+		return nil, fmt.Errorf("cannot marshal an account without a base account")
+	}
+
+	balance := acc.Balance(DenomDefault)
+
+	alias := accountJSON{
+		Address:       acc.Address,
+		EthAddress:    AccAddressToEthAddress(acc.Address),
+		Coins:         acc.Coins,
+		Balance:       &balance,
+		AccountNumber: acc.AccountNumber,
+		Nonce:         acc.Sequence,
+		Root:          acc.Root,
+		CodeHash:      acc.CodeHash,
+	}
+
+	if acc.PubKey != nil {
+		pubKey, ok := acc.PubKey.(crypto.PubKeySecp256k1)
+		if !ok {
+			return nil, fmt.Errorf("unsupported account public key type: %T", acc.PubKey)
+		}
+
+		alias.PubKey = &pubKey
+	}
+
+	return json.Marshal(alias)
 }
 
-// SetBalance sets an account's balance.
-func (acc Account) SetBalance(amt sdk.Int) {
-	acc.SetCoins(sdk.Coins{sdk.NewCoin(DenomDefault, amt)})
+// UnmarshalJSON unmarshals raw JSON bytes into an Account. The bech32 and
+// Ethereum addresses must refer to the same account if both are given. The
+// balance is optional. If given, it must match the amount of the default
+// denomination in the coins, or it is added to them if they hold none of it.
+func (acc *Account) UnmarshalJSON(bz []byte) error {
+	var alias accountJSON
+	if err := json.Unmarshal(bz, &alias); err != nil {
+		return err
+	}
+
+	addr := alias.Address
+	switch {
+	case addr.Empty():
+		addr = EthAddressToAccAddress(alias.EthAddress)
+
+	case alias.EthAddress != (ethcmn.Address{}) && AccAddressToEthAddress(addr) != alias.EthAddress:
+		return fmt.Errorf(
+			"address %s does not match Ethereum address %s", addr, alias.EthAddress.Hex(),
+		)
+	}
+
+	coins := alias.Coins
+	if alias.Balance != nil {
+		amt := coins.AmountOf(DenomDefault)
+
+		switch {
+		case alias.Balance.Sign() < 0:
+			return fmt.Errorf("invalid negative balance %s", alias.Balance)
+
+		case amt.IsZero() && !alias.Balance.IsZero():
+			coins = coins.Plus(sdk.Coins{sdk.NewCoin(DenomDefault, *alias.Balance)})
+
+		case !amt.Equal(*alias.Balance):
+			return fmt.Errorf(
+				"balance %s does not match coins amount %s%s", alias.Balance, amt, DenomDefault,
+			)
+		}
+	}
+
+	acc.BaseAccount = &auth.BaseAccount{
+		Address:       addr,
+		Coins:         coins,
+		AccountNumber: alias.AccountNumber,
+		Sequence:      alias.Nonce,
+	}
+
+	if alias.PubKey != nil {
+		acc.BaseAccount.PubKey = *alias.PubKey
+	}
+
+	acc.Root = alias.Root
+	acc.CodeHash = nil
+
+	if len(alias.CodeHash) != 0 {
+		acc.CodeHash = alias.CodeHash
+	}
+
+	return nil
 }
 
 // ----------------------------------------------------------------------------
@@ -0,0 +1,181 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/cosmos/ethermint/crypto"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAccount(t *testing.T) Account {
+	privKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	pubKey := privKey.PubKey()
+
+	return Account{
+		BaseAccount: &auth.BaseAccount{
+			Address: sdk.AccAddress(pubKey.Address()),
+			Coins: sdk.Coins{
+				sdk.NewInt64Coin(DenomDefault, 100),
+				sdk.NewInt64Coin("stake", 50),
+			},
+			PubKey:        pubKey,
+			AccountNumber: 3,
+			Sequence:      7,
+		},
+		Root:     ethcmn.BytesToHash([]byte{0x1}),
+		CodeHash: ethcrypto.Keccak256([]byte("code")),
+	}
+}
+
+func TestAccountJSON(t *testing.T) {
+	acc := newTestAccount(t)
+
+	bz, err := json.Marshal(acc)
+	require.NoError(t, err)
+
+	var raw map[string]interface{}
+	require.NoError(t, json.Unmarshal(bz, &raw))
+	require.Equal(t, acc.Address.String(), raw["address"])
+	require.Equal(t, strings.ToLower(AccAddressToEthAddress(acc.Address).Hex()), raw["eth_address"])
+	require.Equal(t, "100", raw["balance"])
+	require.Equal(t, float64(7), raw["nonce"])
+	require.Equal(t, hexutil.Encode(acc.CodeHash), raw["code_hash"])
+
+	var acc2 Account
+	require.NoError(t, json.Unmarshal(bz, &acc2))
+	require.Equal(t, acc, acc2)
+
+	// require the Ethereum address to be sufficient
+	delete(raw, "address")
+	bz, err = json.Marshal(raw)
+	require.NoError(t, err)
+
+	acc2 = Account{}
+	require.NoError(t, json.Unmarshal(bz, &acc2))
+	require.Equal(t, acc.Address, acc2.Address)
+
+	// require mismatching addresses to fail
+	raw["address"] = sdk.AccAddress(ethcmn.HexToAddress("0x1").Bytes()).String()
+	bz, err = json.Marshal(raw)
+	require.NoError(t, err)
+	require.Error(t, json.Unmarshal(bz, &acc2))
+}
+
+func TestAccountJSONBalance(t *testing.T) {
+	addr := ethcmn.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+
+	testCases := []struct {
+		json       string
+		expCoins   sdk.Coins
+		expectPass bool
+	}{
+		{
+			`{"eth_address":"` + addr.Hex() + `","balance":"100"}`,
+			sdk.Coins{sdk.NewInt64Coin(DenomDefault, 100)},
+			true,
+		},
+		{
+			`{"eth_address":"` + addr.Hex() + `","coins":[{"denom":"stake","amount":"5"}],"balance":"100"}`,
+			sdk.Coins{sdk.NewInt64Coin(DenomDefault, 100), sdk.NewInt64Coin("stake", 5)},
+			true,
+		},
+		{
+			`{"eth_address":"` + addr.Hex() + `","coins":[{"denom":"` + DenomDefault + `","amount":"100"}]}`,
+			sdk.Coins{sdk.NewInt64Coin(DenomDefault, 100)},
+			true,
+		},
+		{
+			`{"eth_address":"` + addr.Hex() + `","coins":[{"denom":"` + DenomDefault + `","amount":"100"}],"balance":"5"}`,
+			nil,
+			false,
+		},
+		{
+			`{"eth_address":"` + addr.Hex() + `","balance":"-5"}`,
+			nil,
+			false,
+		},
+	}
+
+	for i, tc := range testCases {
+		var acc Account
+
+		err := json.Unmarshal([]byte(tc.json), &acc)
+		if tc.expectPass {
+			require.NoError(t, err, "test case #%d", i)
+			require.Equal(t, tc.expCoins, acc.Coins, "test case #%d", i)
+			require.Equal(t, EthAddressToAccAddress(addr), acc.Address, "test case #%d", i)
+		} else {
+			require.Error(t, err, "test case #%d", i)
+		}
+	}
+}
+
+func TestAccountAminoJSON(t *testing.T) {
+	acc := newTestAccount(t)
+
+	// require the account to round-trip through amino JSON (e.g. genesis files)
+	bz, err := typesCodec.MarshalJSONIndent(&acc, "", "  ")
+	require.NoError(t, err)
+	require.Contains(t, string(bz), `"nonce": 7`)
+
+	var acc2 Account
+	require.NoError(t, typesCodec.UnmarshalJSON(bz, &acc2))
+	require.Equal(t, acc, acc2)
+}
+
+func TestAccountSetBalance(t *testing.T) {
+	acc := newTestAccount(t)
+
+	// require only the balance of the given denomination to change
+	acc.SetBalance(DenomDefault, sdk.NewInt(42))
+	require.Equal(t, sdk.NewInt(42), acc.Balance(DenomDefault))
+	require.Equal(t, sdk.NewInt(50), acc.Balance("stake"))
+
+	// require a new denomination to be added in sorted order
+	acc.SetBalance("atom", sdk.NewInt(7))
+	require.Equal(t, sdk.Coins{
+		sdk.NewInt64Coin(DenomDefault, 42),
+		sdk.NewInt64Coin("atom", 7),
+		sdk.NewInt64Coin("stake", 50),
+	}, acc.GetCoins())
+
+	// require a zero balance to remove the denomination
+	acc.SetBalance(DenomDefault, sdk.ZeroInt())
+	require.True(t, acc.Balance(DenomDefault).IsZero())
+	require.Equal(t, sdk.Coins{
+		sdk.NewInt64Coin("atom", 7),
+		sdk.NewInt64Coin("stake", 50),
+	}, acc.GetCoins())
+}
+
+func TestAccountCopy(t *testing.T) {
+	acc := newTestAccount(t)
+	cpy := acc.Copy()
+	require.Equal(t, &acc, cpy)
+
+	// require changes to the copy not to affect the original account
+	cpy.SetBalance(DenomDefault, sdk.NewInt(1))
+	cpy.Sequence++
+	cpy.CodeHash[0] ^= 0xff
+	cpy.Address[0] ^= 0xff
+
+	require.Equal(t, sdk.NewInt(100), acc.Balance(DenomDefault))
+	require.Equal(t, uint64(7), acc.Sequence)
+	require.Equal(t, ethcrypto.Keccak256([]byte("code")), acc.CodeHash)
+	require.NotEqual(t, acc.Address, cpy.Address)
+
+	// require changes to the original account not to affect the copy
+	acc.SetBalance("stake", sdk.NewInt(2))
+	require.Equal(t, sdk.NewInt(50), cpy.Balance("stake"))
+}
@@ -0,0 +1,81 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+)
+
+// Ethermint bech32 prefixes. Accounts, validators and consensus nodes use the
+// same 20 byte addresses as Ethereum but are displayed in bech32 on the SDK
+// side (CLI, REST and genesis).
+const (
+	// Bech32MainPrefix defines the main Ethermint bech32 prefix of an address.
+	Bech32MainPrefix = "emint"
+
+	// Bech32PrefixAccAddr defines the bech32 prefix of an account's address.
+	Bech32PrefixAccAddr = Bech32MainPrefix
+	// Bech32PrefixAccPub defines the bech32 prefix of an account's public key.
+	Bech32PrefixAccPub = Bech32MainPrefix + "pub"
+	// Bech32PrefixValAddr defines the bech32 prefix of a validator's operator
+	// address.
+	Bech32PrefixValAddr = Bech32MainPrefix + "valoper"
+	// Bech32PrefixValPub defines the bech32 prefix of a validator's operator
+	// public key.
+	Bech32PrefixValPub = Bech32MainPrefix + "valoperpub"
+	// Bech32PrefixConsAddr defines the bech32 prefix of a consensus node
+	// address.
+	Bech32PrefixConsAddr = Bech32MainPrefix + "valcons"
+	// Bech32PrefixConsPub defines the bech32 prefix of a consensus node public
+	// key.
+	Bech32PrefixConsPub = Bech32MainPrefix + "valconspub"
+)
+
+// SetBech32Prefixes sets the Ethermint bech32 prefixes on the given SDK config.
+// It must be called before the config is sealed and before any address is
+// encoded or decoded.
+func SetBech32Prefixes(config *sdk.Config) {
+	config.SetBech32PrefixForAccount(Bech32PrefixAccAddr, Bech32PrefixAccPub)
+	config.SetBech32PrefixForValidator(Bech32PrefixValAddr, Bech32PrefixValPub)
+	config.SetBech32PrefixForConsensusNode(Bech32PrefixConsAddr, Bech32PrefixConsPub)
+}
+
+// ----------------------------------------------------------------------------
+// Address conversion
+// ----------------------------------------------------------------------------
+
+// EthAddressToAccAddress converts an Ethereum address to an SDK account
+// address. Both refer to the same 20 bytes.
+func EthAddressToAccAddress(addr ethcmn.Address) sdk.AccAddress {
+	return sdk.AccAddress(addr.Bytes())
+}
+
+// AccAddressToEthAddress converts an SDK account address to an Ethereum
+// address. Both refer to the same 20 bytes.
+func AccAddressToEthAddress(addr sdk.AccAddress) ethcmn.Address {
+	return ethcmn.BytesToAddress(addr.Bytes())
+}
+
+// ParseAddress parses an account address given either in Ethereum hex format
+// (with or without the 0x prefix) or in bech32 format. It returns an error if
+// the address is in neither format.
+func ParseAddress(addr string) (sdk.AccAddress, error) {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return nil, fmt.Errorf("empty address")
+	}
+
+	if ethcmn.IsHexAddress(addr) {
+		return EthAddressToAccAddress(ethcmn.HexToAddress(addr)), nil
+	}
+
+	accAddr, err := sdk.AccAddressFromBech32(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %s: expected a hex or bech32 address", addr)
+	}
+
+	return accAddr, nil
+}
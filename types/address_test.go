@@ -0,0 +1,51 @@
+package types
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	SetBech32Prefixes(sdk.GetConfig())
+}
+
+func TestAddressConversion(t *testing.T) {
+	ethAddr := ethcmn.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+
+	accAddr := EthAddressToAccAddress(ethAddr)
+	require.Equal(t, ethAddr.Bytes(), accAddr.Bytes())
+	require.Equal(t, "emint1e54rm8un3cfum9r7cpdtcll8xn0cmkpxqm2hqu", accAddr.String())
+	require.Equal(t, ethAddr, AccAddressToEthAddress(accAddr))
+}
+
+func TestParseAddress(t *testing.T) {
+	expected := EthAddressToAccAddress(ethcmn.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"))
+
+	testCases := []struct {
+		addr       string
+		expectPass bool
+	}{
+		{"0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826", true},
+		{"0xcd2a3d9f938e13cd947ec05abc7fe734df8dd826", true},
+		{"CD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826", true},
+		{" emint1e54rm8un3cfum9r7cpdtcll8xn0cmkpxqm2hqu ", true},
+		{"emint1e54rm8un3cfum9r7cpdtcll8xn0cmkpxqm2hqv", false},
+		{"cosmos1e54rm8un3cfum9r7cpdtcll8xn0cmkpxalqpmw", false},
+		{"0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD8", false},
+		{"", false},
+	}
+
+	for i, tc := range testCases {
+		addr, err := ParseAddress(tc.addr)
+		if tc.expectPass {
+			require.NoError(t, err, "test case #%d", i)
+			require.Equal(t, expected, addr, "test case #%d", i)
+		} else {
+			require.Error(t, err, "test case #%d", i)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package evm
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+
+	"github.com/cosmos/ethermint/core"
+	"github.com/cosmos/ethermint/x/evm/tags"
+	"github.com/cosmos/ethermint/x/evm/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// BeginBlocker stores the Ethereum header of the block being processed in the
+// header store. The coinbase of the header is set to the operator address of
+// the block proposer so that the COINBASE opcode and ChainContext.Author refer
+// to it.
+//
+// The first block processed after the upgrade to reference counted code counts
+// the references to the code persisted before (see Keeper.MigrateCodeRefs).
+func BeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock, k Keeper) {
+	if err := k.MigrateCodeRefs(ctx); err != nil {
+		panic(fmt.Sprintf("failed to migrate code references: %s", err))
+	}
+
+	chainContext := core.NewChainContext(ctx, k.headerKey)
+
+	var parentHash ethcmn.Hash
+	if req.Header.Height > 1 {
+		if parent := chainContext.GetHeader(ethcmn.Hash{}, uint64(req.Header.Height-1)); parent != nil {
+			parentHash = parent.Hash()
+		}
+	}
+
+	header := core.NewEthHeader(req.Header, parentHash)
+	header.Coinbase = ProposerAddress(ctx, k.vs, sdk.ConsAddress(req.Header.ProposerAddress))
+
+	chainContext.SetHeader(uint64(req.Header.Height), header)
+}
+
+// EndBlocker sets the parameters of the parameter change proposals that have
+// passed and forgets about the ones that have been rejected or removed (e.g.
+// because their deposit period ended). The expected supply of the EVM
+// denomination is reset when an applied change sets a different denomination.
+//
+// CONTRACT: The governance EndBlocker must be run first so that the proposals
+// ending their voting period in this block are tallied.
+func EndBlocker(ctx sdk.Context, _ abci.RequestEndBlock, k Keeper, fck FeeCollectionKeeper) sdk.Tags {
+	resTags := sdk.NewTags()
+	logger := ctx.Logger().With("module", "x/evm")
+
+	var done []uint64
+
+	k.IterateParamsProposals(ctx, func(proposalID uint64, params types.Params) bool {
+		proposal := k.gk.GetProposal(ctx, proposalID)
+		if proposal == nil {
+			done = append(done, proposalID)
+			return false
+		}
+
+		switch proposal.GetStatus() {
+		case gov.StatusPassed:
+			// parameters are validated on submission, but the validation rules
+			// and the bond and deposit denominations may have changed since
+			if err := k.ValidateParams(ctx, params); err != nil {
+				logger.Error(fmt.Sprintf("not applying parameter change proposal %d: %s", proposalID, err))
+			} else {
+				prevDenom := k.GetParams(ctx).EVMDenom
+				k.SetParams(ctx, params)
+
+				// the supply of the new EVM denomination is conserved from now on
+				if params.EVMDenom != prevDenom {
+					k.SetSupply(ctx, TotalSupply(ctx, k, fck))
+				}
+
+				resTags = resTags.AppendTag(tags.ParamsProposalApplied, []byte(fmt.Sprintf("%d", proposalID)))
+				logger.Info(fmt.Sprintf("applied parameter change proposal %d", proposalID))
+			}
+
+			done = append(done, proposalID)
+
+		case gov.StatusRejected:
+			done = append(done, proposalID)
+		}
+
+		return false
+	})
+
+	for _, proposalID := range done {
+		k.DeleteParamsProposal(ctx, proposalID)
+	}
+
+	return resTags
+}
+
+// ProposerAddress returns the operator address of the validator with the given
+// consensus address as an Ethereum address. The empty address is returned if
+// the validator does not exist (e.g. prior to genesis validators being set).
+func ProposerAddress(ctx sdk.Context, vs ValidatorSet, consAddr sdk.ConsAddress) ethcmn.Address {
+	if len(consAddr) == 0 {
+		return ethcmn.Address{}
+	}
+
+	validator := vs.ValidatorByConsAddr(ctx, consAddr)
+	if validator == nil {
+		return ethcmn.Address{}
+	}
+
+	return ethcmn.BytesToAddress(validator.GetOperator().Bytes())
+}
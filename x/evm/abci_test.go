@@ -0,0 +1,168 @@
+package evm
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+
+	"github.com/cosmos/ethermint/core"
+	emint "github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/evm/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+type mockValidator struct {
+	sdk.Validator
+	operator sdk.ValAddress
+}
+
+func (v mockValidator) GetOperator() sdk.ValAddress { return v.operator }
+
+type mockValidatorSet map[string]sdk.ValAddress
+
+func (vs mockValidatorSet) ValidatorByConsAddr(_ sdk.Context, addr sdk.ConsAddress) sdk.Validator {
+	operator, ok := vs[string(addr)]
+	if !ok {
+		return nil
+	}
+
+	return mockValidator{operator: operator}
+}
+
+func (vs mockValidatorSet) BondDenom(_ sdk.Context) string {
+	return "stake"
+}
+
+func TestBeginBlocker(t *testing.T) {
+	consAddr := sdk.ConsAddress(ethcmn.HexToAddress("0x1").Bytes())
+	operator := sdk.ValAddress(ethcmn.HexToAddress("0x2").Bytes())
+	vs := mockValidatorSet{string(consAddr): operator}
+
+	ts := newTestSetup(t, vs)
+	ctx := ts.ctx
+
+	testCases := []struct {
+		proposer    sdk.ConsAddress
+		expCoinbase ethcmn.Address
+	}{
+		{consAddr, ethcmn.BytesToAddress(operator.Bytes())},
+		{sdk.ConsAddress(ethcmn.HexToAddress("0x3").Bytes()), ethcmn.Address{}},
+		{nil, ethcmn.Address{}},
+	}
+
+	chainContext := core.NewChainContext(ctx, ts.keeper.headerKey)
+
+	for i, tc := range testCases {
+		height := int64(i + 1)
+		req := abci.RequestBeginBlock{
+			Header: abci.Header{Height: height, Time: time.Unix(height, 0), ProposerAddress: tc.proposer},
+		}
+
+		BeginBlocker(ctx, req, ts.keeper)
+
+		header := chainContext.GetHeader(ethcmn.Hash{}, uint64(height))
+		require.NotNil(t, header, "test case #%d", i)
+		require.Equal(t, tc.expCoinbase, header.Coinbase, "test case #%d", i)
+
+		author, err := chainContext.Author(header)
+		require.NoError(t, err, "test case #%d", i)
+		require.Equal(t, tc.expCoinbase, author, "test case #%d", i)
+
+		// require the header to be chained to the previous one
+		if i > 0 {
+			parent := chainContext.GetHeader(ethcmn.Hash{}, uint64(height-1))
+			require.Equal(t, parent.Hash(), header.ParentHash, "test case #%d", i)
+		}
+	}
+}
+
+func TestBeginBlockerMigrateCodeRefs(t *testing.T) {
+	ts := newTestSetup(t, mockValidatorSet{})
+	header := abci.Header{ChainID: "3", Height: 1}
+
+	// code and an account referencing it persisted before references were
+	// counted
+	codeStore := ts.ctx.KVStore(ts.keeper.codeKey)
+	code := []byte("contract code")
+	codeHash := ethcrypto.Keccak256(code)
+	codeStore.Set(codeHash, code)
+
+	acc := ts.ak.NewAccountWithAddress(ts.ctx, sdk.AccAddress(ethcmn.HexToAddress("0x1").Bytes())).(*emint.Account)
+	acc.CodeHash = codeHash
+	ts.ak.SetAccount(ts.ctx, acc)
+
+	// require the references to be counted by the first block only
+	BeginBlocker(ts.ctx, abci.RequestBeginBlock{Header: header}, ts.keeper)
+	require.Equal(t, uint64(1), binary.BigEndian.Uint64(codeStore.Get(types.CodeRefCountKey(codeHash))))
+
+	codeStore.Delete(types.CodeRefCountKey(codeHash))
+
+	BeginBlocker(ts.ctx, abci.RequestBeginBlock{Header: header}, ts.keeper)
+	require.Nil(t, codeStore.Get(types.CodeRefCountKey(codeHash)))
+}
+
+func TestEndBlocker(t *testing.T) {
+	ts := newTestSetup(t, mockValidatorSet{})
+	fck := mockFeeCollectionKeeper{sdk.NewInt64Coin("aphoton", 10)}
+
+	ts.keeper.SetSupply(ts.ctx, sdk.NewInt64Coin(emint.DenomDefault, 100))
+
+	params := types.DefaultParams()
+	params.EVMDenom = "aphoton"
+
+	// submit proposals that will pass, be rejected, stay in their voting period
+	// and be removed
+	for i := 0; i < 4; i++ {
+		proposal := ts.gk.NewTextProposal(ts.ctx, "title", "description", gov.ProposalTypeParameterChange)
+		ts.keeper.SetParamsProposal(ts.ctx, proposal.GetProposalID(), params)
+	}
+
+	ts.gk.proposals[1].SetStatus(gov.StatusPassed)
+	ts.gk.proposals[2].SetStatus(gov.StatusRejected)
+	ts.gk.proposals[3].SetStatus(gov.StatusVotingPeriod)
+	delete(ts.gk.proposals, 4)
+
+	tags := EndBlocker(ts.ctx, abci.RequestEndBlock{}, ts.keeper, fck)
+	require.Len(t, tags, 1)
+	require.Equal(t, []byte("1"), tags[0].Value)
+
+	// require the parameters of the passed proposal to be set and the expected
+	// supply to be reset to the supply of the new EVM denomination
+	require.Equal(t, params, ts.keeper.GetParams(ts.ctx))
+
+	supply, found := ts.keeper.GetSupply(ts.ctx)
+	require.True(t, found)
+	require.True(t, supply.IsEqual(sdk.NewInt64Coin("aphoton", 10)))
+
+	// require only the proposal in its voting period to be pending
+	var pending []uint64
+	ts.keeper.IterateParamsProposals(ts.ctx, func(proposalID uint64, _ types.Params) bool {
+		pending = append(pending, proposalID)
+		return false
+	})
+	require.Equal(t, []uint64{3}, pending)
+
+	// require a passed proposal setting the bond denomination as the EVM
+	// denomination not to be applied
+	invalid := params
+	invalid.EVMDenom = "stake"
+
+	proposal := ts.gk.NewTextProposal(ts.ctx, "title", "description", gov.ProposalTypeParameterChange)
+	proposal.SetStatus(gov.StatusPassed)
+	ts.keeper.SetParamsProposal(ts.ctx, proposal.GetProposalID(), invalid)
+
+	tags = EndBlocker(ts.ctx, abci.RequestEndBlock{}, ts.keeper, fck)
+	require.Empty(t, tags)
+	require.Equal(t, params, ts.keeper.GetParams(ts.ctx))
+
+	_, found = ts.keeper.GetParamsProposal(ts.ctx, proposal.GetProposalID())
+	require.False(t, found)
+}
@@ -0,0 +1,28 @@
+package evm
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+)
+
+// ValidatorSet defines the subset of the validator set needed by the EVM
+// module to resolve the proposer of a block and the bond denomination.
+type ValidatorSet interface {
+	ValidatorByConsAddr(sdk.Context, sdk.ConsAddress) sdk.Validator
+	BondDenom(sdk.Context) string
+}
+
+// GovKeeper defines the subset of the governance keeper needed by the EVM
+// module to submit and follow parameter change proposals.
+type GovKeeper interface {
+	NewTextProposal(ctx sdk.Context, title, description string, proposalType gov.ProposalKind) gov.Proposal
+	AddDeposit(ctx sdk.Context, proposalID uint64, depositorAddr sdk.AccAddress, depositAmount sdk.Coins) (sdk.Error, bool)
+	GetProposal(ctx sdk.Context, proposalID uint64) gov.Proposal
+	GetDepositParams(ctx sdk.Context) gov.DepositParams
+}
+
+// FeeCollectionKeeper defines the subset of the fee collection keeper needed by
+// the EVM module to account for the collected fees in the total supply.
+type FeeCollectionKeeper interface {
+	GetCollectedFees(ctx sdk.Context) sdk.Coins
+}
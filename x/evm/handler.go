@@ -1 +1,331 @@
 package evm
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+	govtags "github.com/cosmos/cosmos-sdk/x/gov/tags"
+
+	"github.com/cosmos/ethermint/core"
+	emint "github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/evm/tags"
+	"github.com/cosmos/ethermint/x/evm/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcore "github.com/ethereum/go-ethereum/core"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// NewHandler returns a handler for the EVM module messages. Each Ethereum
+// transaction message is applied to the state through the EVM configured with
+// the chain config set in the EVM module parameters.
+func NewHandler(k Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case types.EthereumTxMsg:
+			return handleEthereumTxMsg(ctx, &msg, k)
+
+		case *types.EthereumTxMsg:
+			return handleEthereumTxMsg(ctx, msg, k)
+
+		case types.SubmitParamsProposalMsg:
+			return handleSubmitParamsProposalMsg(ctx, msg, k)
+
+		default:
+			return sdk.ErrUnknownRequest(fmt.Sprintf("unrecognized EVM message type: %T", msg)).Result()
+		}
+	}
+}
+
+// NewGovHandler wraps the given gov module handler to reject parameter change
+// proposals submitted through the gov module. The gov module of the SDK cannot
+// route proposals to other modules, so these would never be applied. EVM
+// parameter changes are proposed through SubmitParamsProposalMsg instead.
+// Deposits of the EVM denomination are rejected as well (see SupplyInvariant).
+func NewGovHandler(k Keeper, govHandler sdk.Handler) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		switch msg := msg.(type) {
+		case gov.MsgSubmitProposal:
+			if msg.ProposalType == gov.ProposalTypeParameterChange {
+				return sdk.ErrUnknownRequest(
+					"parameter change proposals must be submitted through the EVM module",
+				).Result()
+			}
+
+			if err := validateDeposit(ctx, k, msg.InitialDeposit); err != nil {
+				return err.Result()
+			}
+
+		case gov.MsgDeposit:
+			if err := validateDeposit(ctx, k, msg.Amount); err != nil {
+				return err.Result()
+			}
+		}
+
+		return govHandler(ctx, msg)
+	}
+}
+
+// validateDeposit returns an error if the given proposal deposit contains coins
+// of the EVM denomination.
+func validateDeposit(ctx sdk.Context, k Keeper, deposit sdk.Coins) sdk.Error {
+	denom := k.GetParams(ctx).EVMDenom
+	if !deposit.AmountOf(denom).IsZero() {
+		return sdk.ErrInvalidCoins(fmt.Sprintf("deposits cannot contain the EVM denomination %s", denom))
+	}
+
+	return nil
+}
+
+// handleEthereumTxMsg applies an Ethereum transaction message. The transaction
+// is executed by an EVM built from the chain config set in the parameters and
+// the Ethereum header of the current block. The resulting state changes are
+// committed to the KVStores of the given context.
+func handleEthereumTxMsg(ctx sdk.Context, msg *types.EthereumTxMsg, k Keeper) sdk.Result {
+	params := k.GetParams(ctx)
+	chainConfig := params.ChainConfig
+
+	chainID, err := types.GetChainID(ctx, chainConfig)
+	if err != nil {
+		return emint.ErrInvalidChainID(err.Error()).Result()
+	}
+
+	sender, err := msg.VerifySig(chainID)
+	if err != nil {
+		return sdk.ErrUnauthorized("signature verification failed").Result()
+	}
+
+	// the transaction is executed on a cache of the state, which is discarded if
+	// the execution breaks a rule of the EVM module
+	cacheCtx, writeCache := ctx.CacheContext()
+
+	stateDB, err := k.NewCommitStateDB(cacheCtx)
+	if err != nil {
+		return sdk.ErrInternal(err.Error()).Result()
+	}
+
+	// enforce the deployer and caller allow-lists
+	contractCall := msg.To() != nil && stateDB.GetCodeSize(*msg.To()) > 0
+	if err := params.ValidateSender(sender, msg.To() == nil, contractCall); err != nil {
+		return sdk.ErrUnauthorized(err.Error()).Result()
+	}
+
+	chainContext := core.NewChainContext(ctx, k.headerKey)
+
+	// use the header set in the BeginBlocker, if any
+	header := chainContext.GetHeader(ethcmn.Hash{}, uint64(ctx.BlockHeight()))
+	if header == nil {
+		header = core.NewEthHeader(ctx.BlockHeader(), ethcmn.Hash{})
+	}
+
+	ethConfig := chainConfig.EthereumConfig(chainID)
+	ethMsg := ethtypes.NewMessage(
+		sender, msg.To(), msg.Data.AccountNonce, msg.Data.Amount,
+		msg.Data.GasLimit, msg.Data.Price, msg.Data.Payload, true,
+	)
+
+	vmCtx := ethcore.NewEVMContext(ethMsg, header, chainContext, nil)
+	evm := ethvm.NewEVM(vmCtx, stateDB, ethConfig, ethvm.Config{})
+	gp := new(ethcore.GasPool).AddGas(msg.Data.GasLimit)
+
+	txHash := msg.Hash()
+	stateDB.Prepare(txHash, header.Hash(), 0)
+
+	ret, gasUsed, failed, err := ethcore.ApplyMessage(evm, ethMsg, gp)
+	if err != nil {
+		return sdk.ErrInternal(fmt.Sprintf("failed to apply transaction: %s", err)).Result()
+	}
+
+	// a DB error fails the transaction and discards all of its state changes
+	if err := stateDB.Error(); err != nil {
+		return sdk.ErrInternal(err.Error()).Result()
+	}
+
+	height := big.NewInt(ctx.BlockHeight())
+
+	root, err := stateDB.Commit(ethConfig.IsEIP158(height))
+	if err != nil {
+		return sdk.ErrInternal(fmt.Sprintf("failed to commit state: %s", err)).Result()
+	}
+
+	logs := stateDB.GetLogs(txHash)
+
+	// The EVM cannot be made to fail on contract code (init or runtime code) not
+	// allowed by the parameters, nor on coins being minted or burned (e.g. by a
+	// contract self-destructing with itself as the beneficiary). Such an
+	// execution is discarded and the transaction fails as if it ran out of gas,
+	// so that its gas is still paid and its nonce still used.
+	var ruleErr error
+	if msg.To() == nil {
+		if err := params.ValidateOpcodes(msg.Data.Payload); err != nil {
+			ruleErr = fmt.Errorf("invalid init code: %s", err)
+		}
+	}
+
+	if ruleErr == nil {
+		ruleErr = stateDB.CodeError()
+	}
+
+	if delta := stateDB.SupplyDelta(); ruleErr == nil && !delta.IsZero() {
+		ruleErr = fmt.Errorf("total supply of %s changed by %s", params.EVMDenom, delta)
+	}
+
+	var resLog string
+	if ruleErr != nil {
+		root, err = chargeFailedTx(ctx, k, ethMsg, vmCtx.Coinbase, ethConfig.IsEIP158(height))
+		if err != nil {
+			return sdk.ErrInternal(fmt.Sprintf("failed to charge transaction: %s", err)).Result()
+		}
+
+		ret, gasUsed, failed, logs = nil, msg.Data.GasLimit, true, nil
+		resLog = fmt.Sprintf("EVM execution failed: %s", ruleErr)
+	} else {
+		writeCache()
+
+		if failed {
+			// state changes other than the fee payment and nonce increment are
+			// reverted by the EVM, but the transaction is still included
+			resLog = "EVM execution failed"
+		}
+	}
+
+	k.SetReceipt(ctx, newReceipt(ethMsg, txHash, root, gasUsed, failed, logs, ethConfig.IsByzantium(height)))
+
+	return sdk.Result{
+		Data:      ret,
+		GasWanted: msg.Data.GasLimit,
+		GasUsed:   gasUsed,
+		Log:       resLog,
+		Tags:      ethTxTags(ethMsg, txHash, gasUsed, failed, logs),
+	}
+}
+
+// chargeFailedTx applies an Ethereum transaction whose execution has been
+// discarded: the sender pays for all the gas of the transaction to the coinbase
+// and its nonce is incremented. It returns the intermediate state root.
+func chargeFailedTx(
+	ctx sdk.Context, k Keeper, ethMsg ethtypes.Message, coinbase ethcmn.Address, deleteEmptyObjects bool,
+) (ethcmn.Hash, error) {
+
+	stateDB, err := k.NewCommitStateDB(ctx)
+	if err != nil {
+		return ethcmn.Hash{}, err
+	}
+
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(ethMsg.Gas()), ethMsg.GasPrice())
+
+	stateDB.SubBalance(ethMsg.From(), fee)
+	stateDB.SetNonce(ethMsg.From(), stateDB.GetNonce(ethMsg.From())+1)
+	stateDB.AddBalance(coinbase, fee)
+
+	if err := stateDB.Error(); err != nil {
+		return ethcmn.Hash{}, err
+	}
+
+	return stateDB.Commit(deleteEmptyObjects)
+}
+
+// newReceipt returns the receipt of an applied Ethereum transaction. As in
+// Ethereum, it holds the intermediate state root after the transaction prior
+// to the Byzantium fork and the execution status afterwards. The gas used by
+// the previous transactions of the block is not tracked, so the cumulative gas
+// used is the gas used by the transaction.
+func newReceipt(
+	ethMsg ethtypes.Message, txHash, root ethcmn.Hash, gasUsed uint64, failed bool,
+	logs []*ethtypes.Log, byzantium bool,
+) *ethtypes.Receipt {
+
+	var postState []byte
+	if !byzantium {
+		postState = root.Bytes()
+	}
+
+	receipt := ethtypes.NewReceipt(postState, failed, gasUsed)
+	receipt.TxHash = txHash
+	receipt.GasUsed = gasUsed
+	receipt.Logs = logs
+	receipt.Bloom = ethtypes.CreateBloom(ethtypes.Receipts{receipt})
+
+	if ethMsg.To() == nil {
+		receipt.ContractAddress = ethcrypto.CreateAddress(ethMsg.From(), ethMsg.Nonce())
+	}
+
+	return receipt
+}
+
+// ethTxTags returns the tags of an applied Ethereum transaction: the sender,
+// the recipient or created contract, the gas used, the transaction hash and
+// the address and topics of each log.
+func ethTxTags(
+	ethMsg ethtypes.Message, txHash ethcmn.Hash, gasUsed uint64, failed bool, logs []*ethtypes.Log,
+) sdk.Tags {
+
+	resTags := sdk.NewTags(
+		tags.Action, []byte(tags.ActionEthereumTx),
+		tags.Sender, tags.Address(ethMsg.From()),
+		tags.TxHash, tags.Hash(txHash),
+		tags.GasUsed, []byte(strconv.FormatUint(gasUsed, 10)),
+	)
+
+	if ethMsg.To() != nil {
+		resTags = resTags.AppendTag(tags.Recipient, tags.Address(*ethMsg.To()))
+	} else if !failed {
+		contractAddr := ethcrypto.CreateAddress(ethMsg.From(), ethMsg.Nonce())
+		resTags = resTags.AppendTag(tags.ContractAddress, tags.Address(contractAddr))
+	}
+
+	if failed {
+		resTags = resTags.AppendTag(tags.Failed, []byte("true"))
+	}
+
+	for _, log := range logs {
+		resTags = resTags.AppendTag(tags.LogAddress, tags.Address(log.Address))
+
+		for _, topic := range log.Topics {
+			resTags = resTags.AppendTag(tags.LogTopic, tags.Hash(topic))
+		}
+	}
+
+	return resTags
+}
+
+// handleSubmitParamsProposalMsg submits a governance proposal to change the EVM
+// module parameters. The proposed parameters are kept until the proposal is
+// either passed, in which case they are set in the EndBlocker, or rejected.
+func handleSubmitParamsProposalMsg(ctx sdk.Context, msg types.SubmitParamsProposalMsg, k Keeper) sdk.Result {
+	if err := k.ValidateParams(ctx, msg.Params); err != nil {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("invalid proposed params: %s", err)).Result()
+	}
+
+	if err := validateDeposit(ctx, k, msg.InitialDeposit); err != nil {
+		return err.Result()
+	}
+
+	proposal := k.gk.NewTextProposal(ctx, msg.Title, msg.Description, gov.ProposalTypeParameterChange)
+	proposalID := proposal.GetProposalID()
+
+	k.SetParamsProposal(ctx, proposalID, msg.Params)
+
+	err, votingStarted := k.gk.AddDeposit(ctx, proposalID, msg.Proposer, msg.InitialDeposit)
+	if err != nil {
+		return err.Result()
+	}
+
+	proposalIDBytes := k.cdc.MustMarshalBinaryLengthPrefixed(proposalID)
+
+	resTags := sdk.NewTags(
+		govtags.Proposer, []byte(msg.Proposer.String()),
+		govtags.ProposalID, proposalIDBytes,
+	)
+
+	if votingStarted {
+		resTags = resTags.AppendTag(govtags.VotingPeriodStart, proposalIDBytes)
+	}
+
+	return sdk.Result{Data: proposalIDBytes, Tags: resTags}
+}
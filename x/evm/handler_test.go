@@ -0,0 +1,550 @@
+package evm
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+	"github.com/cosmos/cosmos-sdk/x/params"
+
+	"github.com/cosmos/ethermint/crypto"
+	emint "github.com/cosmos/ethermint/types"
+	"github.com/cosmos/ethermint/x/evm/tags"
+	"github.com/cosmos/ethermint/x/evm/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+type testSetup struct {
+	ctx        sdk.Context
+	ak         auth.AccountKeeper
+	gk         *mockGovKeeper
+	paramSpace params.Subspace
+	keeper     Keeper
+	handler    sdk.Handler
+}
+
+func newTestSetup(t *testing.T, vs ValidatorSet) testSetup {
+	db := dbm.NewMemDB()
+
+	accKey := sdk.NewKVStoreKey("acc")
+	evmKey := sdk.NewKVStoreKey("evm")
+	storageKey := sdk.NewKVStoreKey("storage")
+	codeKey := sdk.NewKVStoreKey("code")
+	headerKey := sdk.NewKVStoreKey("header")
+	paramsKey := sdk.NewKVStoreKey("params")
+	tParamsKey := sdk.NewTransientStoreKey("transient_params")
+
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(accKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(evmKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(storageKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(codeKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(headerKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(paramsKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(tParamsKey, sdk.StoreTypeTransient, db)
+	require.NoError(t, ms.LoadLatestVersion())
+
+	cdc := codec.New()
+	types.RegisterCodec(cdc)
+	emint.RegisterCodec(cdc)
+	auth.RegisterCodec(cdc)
+	sdk.RegisterCodec(cdc)
+	codec.RegisterCrypto(cdc)
+
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "3", Height: 1}, false, log.NewNopLogger())
+
+	ak := auth.NewAccountKeeper(cdc, accKey, emint.ProtoBaseAccount)
+	gk := newMockGovKeeper()
+	pk := params.NewKeeper(cdc, paramsKey, tParamsKey)
+	paramSpace := pk.Subspace(types.DefaultParamspace).WithTypeTable(types.ParamTypeTable())
+
+	keeper := NewKeeper(cdc, evmKey, storageKey, codeKey, headerKey, ak, gk, vs, paramSpace)
+
+	return testSetup{
+		ctx:        ctx,
+		ak:         ak,
+		gk:         gk,
+		paramSpace: paramSpace,
+		keeper:     keeper,
+		handler:    NewHandler(keeper),
+	}
+}
+
+// mockGovKeeper implements the GovKeeper interface. Proposals enter their
+// voting period as soon as they have a deposit.
+type mockGovKeeper struct {
+	proposals map[uint64]gov.Proposal
+	nextID    uint64
+}
+
+func newMockGovKeeper() *mockGovKeeper {
+	return &mockGovKeeper{proposals: make(map[uint64]gov.Proposal), nextID: 1}
+}
+
+func (gk *mockGovKeeper) NewTextProposal(
+	_ sdk.Context, title, description string, proposalType gov.ProposalKind,
+) gov.Proposal {
+
+	proposal := &gov.TextProposal{}
+	proposal.SetProposalID(gk.nextID)
+	proposal.SetTitle(title)
+	proposal.SetDescription(description)
+	proposal.SetProposalType(proposalType)
+	proposal.SetStatus(gov.StatusDepositPeriod)
+
+	gk.proposals[gk.nextID] = proposal
+	gk.nextID++
+
+	return proposal
+}
+
+func (gk *mockGovKeeper) AddDeposit(
+	_ sdk.Context, proposalID uint64, _ sdk.AccAddress, depositAmount sdk.Coins,
+) (sdk.Error, bool) {
+
+	proposal, ok := gk.proposals[proposalID]
+	if !ok {
+		return gov.ErrUnknownProposal(gov.DefaultCodespace, proposalID), false
+	}
+
+	if depositAmount.IsZero() || proposal.GetStatus() != gov.StatusDepositPeriod {
+		return nil, false
+	}
+
+	proposal.SetStatus(gov.StatusVotingPeriod)
+	return nil, true
+}
+
+func (gk *mockGovKeeper) GetProposal(_ sdk.Context, proposalID uint64) gov.Proposal {
+	return gk.proposals[proposalID]
+}
+
+func (gk *mockGovKeeper) GetDepositParams(_ sdk.Context) gov.DepositParams {
+	return gov.DepositParams{MinDeposit: sdk.Coins{sdk.NewInt64Coin("stake", 10)}}
+}
+
+func TestHandleEthereumTxMsg(t *testing.T) {
+	ts := newTestSetup(t, mockValidatorSet{})
+
+	priv, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	sender := ethcrypto.PubkeyToAddress(priv.ToECDSA().PublicKey)
+	recipient := ethcmn.HexToAddress("0x1")
+
+	acc := ts.ak.NewAccountWithAddress(ts.ctx, sdk.AccAddress(sender.Bytes()))
+	require.NoError(t, acc.SetCoins(sdk.Coins{sdk.NewInt64Coin(emint.DenomDefault, 100000)}))
+	ts.ak.SetAccount(ts.ctx, acc)
+
+	// require a transaction signed with the Tendermint chain ID to be applied
+	msg := types.NewEthereumTxMsg(0, recipient, big.NewInt(100), 21000, big.NewInt(1), nil)
+	msg.Sign(big.NewInt(3), priv.ToECDSA())
+
+	res := ts.handler(ts.ctx, *msg)
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, uint64(21000), res.GasUsed)
+
+	senderAcc := ts.ak.GetAccount(ts.ctx, sdk.AccAddress(sender.Bytes()))
+	require.Equal(t, uint64(1), senderAcc.GetSequence())
+	require.Equal(t, int64(100000-100-21000), senderAcc.GetCoins().AmountOf(emint.DenomDefault).Int64())
+
+	recipientAcc := ts.ak.GetAccount(ts.ctx, sdk.AccAddress(recipient.Bytes()))
+	require.NotNil(t, recipientAcc)
+	require.Equal(t, int64(100), recipientAcc.GetCoins().AmountOf(emint.DenomDefault).Int64())
+
+	// require the chain ID of the chain config to take precedence
+	params := types.DefaultParams()
+	params.ChainConfig.ChainID = 8
+	ts.paramSpace.SetParamSet(ts.ctx, &params)
+
+	msg = types.NewEthereumTxMsg(1, recipient, big.NewInt(100), 21000, big.NewInt(1), nil)
+	msg.Sign(big.NewInt(3), priv.ToECDSA())
+
+	res = ts.handler(ts.ctx, *msg)
+	require.Equal(t, sdk.CodeUnauthorized, res.Code)
+
+	msg.Sign(big.NewInt(8), priv.ToECDSA())
+
+	res = ts.handler(ts.ctx, *msg)
+	require.True(t, res.IsOK(), res.Log)
+}
+
+func TestHandleEthereumTxMsgTags(t *testing.T) {
+	ts := newTestSetup(t, mockValidatorSet{})
+
+	priv, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	sender := ethcrypto.PubkeyToAddress(priv.ToECDSA().PublicKey)
+	recipient := ethcmn.HexToAddress("0x1")
+
+	acc := ts.ak.NewAccountWithAddress(ts.ctx, sdk.AccAddress(sender.Bytes()))
+	require.NoError(t, acc.SetCoins(sdk.Coins{sdk.NewInt64Coin(emint.DenomDefault, 1000000)}))
+	ts.ak.SetAccount(ts.ctx, acc)
+
+	requireTag := func(resTags sdk.Tags, key string, value []byte) {
+		for _, tag := range resTags {
+			if string(tag.Key) == key && bytes.Equal(tag.Value, value) {
+				return
+			}
+		}
+
+		require.Fail(t, "missing tag", "%s=%s", key, value)
+	}
+
+	// require a value transfer to be tagged with its sender and recipient
+	msg := types.NewEthereumTxMsg(0, recipient, big.NewInt(100), 21000, big.NewInt(1), nil)
+	msg.Sign(big.NewInt(3), priv.ToECDSA())
+
+	res := ts.handler(ts.ctx, *msg)
+	require.True(t, res.IsOK(), res.Log)
+
+	requireTag(res.Tags, tags.Action, []byte(tags.ActionEthereumTx))
+	requireTag(res.Tags, tags.Sender, []byte(strings.ToLower(sender.Hex())))
+	requireTag(res.Tags, tags.Recipient, []byte(strings.ToLower(recipient.Hex())))
+	requireTag(res.Tags, tags.TxHash, []byte(msg.Hash().Hex()))
+	requireTag(res.Tags, tags.GasUsed, []byte("21000"))
+
+	// require a contract creation emitting a log to be tagged with the created
+	// contract and the log address and topic
+	topic := ethcrypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+	initCode := append([]byte{byte(ethvm.PUSH32)}, topic.Bytes()...)
+	initCode = append(initCode, byte(ethvm.PUSH1), 0, byte(ethvm.PUSH1), 0, byte(ethvm.LOG1), byte(ethvm.STOP))
+
+	msg = types.NewEthereumTxMsgContract(1, big.NewInt(0), 100000, big.NewInt(1), initCode)
+	msg.Sign(big.NewInt(3), priv.ToECDSA())
+
+	res = ts.handler(ts.ctx, *msg)
+	require.True(t, res.IsOK(), res.Log)
+
+	contractAddr := []byte(strings.ToLower(ethcrypto.CreateAddress(sender, 1).Hex()))
+	requireTag(res.Tags, tags.ContractAddress, contractAddr)
+	requireTag(res.Tags, tags.LogAddress, contractAddr)
+	requireTag(res.Tags, tags.LogTopic, []byte(topic.Hex()))
+}
+
+func TestHandleEthereumTxMsgReceipt(t *testing.T) {
+	priv, err := ethcrypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	require.NoError(t, err)
+
+	sender := ethcrypto.PubkeyToAddress(priv.PublicKey)
+	recipient := ethcmn.HexToAddress("0x1")
+
+	// applyTx applies a value transfer in a new chain and returns its receipt
+	applyTx := func(byzantium bool, amount int64) *ethtypes.Receipt {
+		ts := newTestSetup(t, mockValidatorSet{})
+
+		acc := ts.ak.NewAccountWithAddress(ts.ctx, sdk.AccAddress(sender.Bytes()))
+		require.NoError(t, acc.SetCoins(sdk.Coins{sdk.NewInt64Coin(emint.DenomDefault, 100000)}))
+		ts.ak.SetAccount(ts.ctx, acc)
+
+		params := types.DefaultParams()
+		if !byzantium {
+			params.ChainConfig.ByzantiumBlock = -1
+			params.ChainConfig.ConstantinopleBlock = -1
+			params.ChainConfig.PetersburgBlock = -1
+		}
+
+		ts.keeper.SetParams(ts.ctx, params)
+
+		msg := types.NewEthereumTxMsg(0, recipient, big.NewInt(amount), 21000, big.NewInt(1), nil)
+		msg.Sign(big.NewInt(3), priv)
+
+		res := ts.handler(ts.ctx, *msg)
+		require.True(t, res.IsOK(), res.Log)
+
+		receipt, found := ts.keeper.GetReceipt(ts.ctx, msg.Hash())
+		require.True(t, found)
+		require.Equal(t, msg.Hash(), receipt.TxHash)
+		require.Equal(t, uint64(21000), receipt.GasUsed)
+		require.Equal(t, ethcmn.Address{}, receipt.ContractAddress)
+
+		return receipt
+	}
+
+	// require the receipt to hold the execution status after Byzantium
+	receipt := applyTx(true, 100)
+	require.Empty(t, receipt.PostState)
+	require.Equal(t, ethtypes.ReceiptStatusSuccessful, receipt.Status)
+
+	// require the receipt to hold the intermediate state root prior to
+	// Byzantium, which depends only on the state transitions
+	receipt = applyTx(false, 100)
+	require.Len(t, receipt.PostState, ethcmn.HashLength)
+	require.NotEqual(t, ethcmn.Hash{}, ethcmn.BytesToHash(receipt.PostState))
+	require.Equal(t, receipt.PostState, applyTx(false, 100).PostState)
+	require.NotEqual(t, receipt.PostState, applyTx(false, 200).PostState)
+}
+
+func TestHandleEthereumTxMsgAllowLists(t *testing.T) {
+	ts := newTestSetup(t, mockValidatorSet{})
+
+	priv, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	sender := ethcrypto.PubkeyToAddress(priv.ToECDSA().PublicKey)
+	other := ethcmn.HexToAddress("0x2")
+
+	acc := ts.ak.NewAccountWithAddress(ts.ctx, sdk.AccAddress(sender.Bytes()))
+	require.NoError(t, acc.SetCoins(sdk.Coins{sdk.NewInt64Coin(emint.DenomDefault, 1000000)}))
+	ts.ak.SetAccount(ts.ctx, acc)
+
+	setAllowLists := func(deployers, callers []string) {
+		params := types.DefaultParams()
+		params.AllowedDeployers = deployers
+		params.AllowedCallers = callers
+		ts.keeper.SetParams(ts.ctx, params)
+	}
+
+	// init code deploying a contract made of a single STOP opcode
+	initCode := []byte{
+		byte(ethvm.PUSH1), byte(ethvm.STOP), byte(ethvm.PUSH1), 0, byte(ethvm.MSTORE8),
+		byte(ethvm.PUSH1), 1, byte(ethvm.PUSH1), 0, byte(ethvm.RETURN),
+	}
+
+	// require a deployer missing from the allow-list to be rejected
+	setAllowLists([]string{other.Hex()}, []string{other.Hex()})
+
+	msg := types.NewEthereumTxMsgContract(0, big.NewInt(0), 100000, big.NewInt(1), initCode)
+	msg.Sign(big.NewInt(3), priv.ToECDSA())
+
+	res := ts.handler(ts.ctx, *msg)
+	require.Equal(t, sdk.CodeUnauthorized, res.Code)
+
+	setAllowLists([]string{other.Hex(), sender.Hex()}, []string{other.Hex()})
+
+	res = ts.handler(ts.ctx, *msg)
+	require.True(t, res.IsOK(), res.Log)
+
+	contractAddr := ethcrypto.CreateAddress(sender, 0)
+
+	// require a caller missing from the allow-list to be rejected while value
+	// transfers to accounts without code are still allowed
+	msg = types.NewEthereumTxMsg(1, contractAddr, big.NewInt(0), 100000, big.NewInt(1), nil)
+	msg.Sign(big.NewInt(3), priv.ToECDSA())
+
+	res = ts.handler(ts.ctx, *msg)
+	require.Equal(t, sdk.CodeUnauthorized, res.Code)
+
+	msg = types.NewEthereumTxMsg(1, other, big.NewInt(100), 21000, big.NewInt(1), nil)
+	msg.Sign(big.NewInt(3), priv.ToECDSA())
+
+	res = ts.handler(ts.ctx, *msg)
+	require.True(t, res.IsOK(), res.Log)
+
+	setAllowLists(nil, []string{sender.Hex()})
+
+	msg = types.NewEthereumTxMsg(2, contractAddr, big.NewInt(0), 100000, big.NewInt(1), nil)
+	msg.Sign(big.NewInt(3), priv.ToECDSA())
+
+	res = ts.handler(ts.ctx, *msg)
+	require.True(t, res.IsOK(), res.Log)
+}
+
+func TestHandleEthereumTxMsgSupplyChange(t *testing.T) {
+	ts := newTestSetup(t, mockValidatorSet{})
+
+	priv, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	sender := ethcrypto.PubkeyToAddress(priv.ToECDSA().PublicKey)
+
+	acc := ts.ak.NewAccountWithAddress(ts.ctx, sdk.AccAddress(sender.Bytes()))
+	require.NoError(t, acc.SetCoins(sdk.Coins{sdk.NewInt64Coin(emint.DenomDefault, 1000000)}))
+	ts.ak.SetAccount(ts.ctx, acc)
+
+	// require a contract self-destructing with itself as the beneficiary, which
+	// burns the value it was created with, to fail while still paying for all
+	// of its gas and using its nonce
+	initCode := []byte{byte(ethvm.ADDRESS), byte(ethvm.SELFDESTRUCT)}
+
+	msg := types.NewEthereumTxMsgContract(0, big.NewInt(100), 100000, big.NewInt(1), initCode)
+	msg.Sign(big.NewInt(3), priv.ToECDSA())
+
+	res := ts.handler(ts.ctx, *msg)
+	require.True(t, res.IsOK(), res.Log)
+	require.Contains(t, res.Log, "EVM execution failed")
+	require.Equal(t, uint64(100000), res.GasUsed)
+
+	senderAcc := ts.ak.GetAccount(ts.ctx, sdk.AccAddress(sender.Bytes()))
+	require.Equal(t, uint64(1), senderAcc.GetSequence())
+	require.Equal(t, int64(1000000-100000), senderAcc.GetCoins().AmountOf(emint.DenomDefault).Int64())
+
+	contractAddr := ethcrypto.CreateAddress(sender, 0)
+	require.Nil(t, ts.ak.GetAccount(ts.ctx, sdk.AccAddress(contractAddr.Bytes())))
+
+	receipt, found := ts.keeper.GetReceipt(ts.ctx, msg.Hash())
+	require.True(t, found)
+	require.Equal(t, ethtypes.ReceiptStatusFailed, receipt.Status)
+}
+
+func TestHandleEthereumTxMsgDisallowedCode(t *testing.T) {
+	ts := newTestSetup(t, mockValidatorSet{})
+
+	priv, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	sender := ethcrypto.PubkeyToAddress(priv.ToECDSA().PublicKey)
+
+	acc := ts.ak.NewAccountWithAddress(ts.ctx, sdk.AccAddress(sender.Bytes()))
+	require.NoError(t, acc.SetCoins(sdk.Coins{sdk.NewInt64Coin(emint.DenomDefault, 1000000)}))
+	ts.ak.SetAccount(ts.ctx, acc)
+
+	params := types.DefaultParams()
+	params.AllowedOpcodes = []string{"PUSH1", "MSTORE8", "RETURN"}
+	ts.keeper.SetParams(ts.ctx, params)
+
+	// init code deploying a contract made of a single STOP opcode, which is not
+	// allowed
+	initCode := []byte{
+		byte(ethvm.PUSH1), byte(ethvm.STOP), byte(ethvm.PUSH1), 0, byte(ethvm.MSTORE8),
+		byte(ethvm.PUSH1), 1, byte(ethvm.PUSH1), 0, byte(ethvm.RETURN),
+	}
+
+	// require the creation to fail while still paying for all of its gas and
+	// using its nonce
+	msg := types.NewEthereumTxMsgContract(0, big.NewInt(100), 100000, big.NewInt(1), initCode)
+	msg.Sign(big.NewInt(3), priv.ToECDSA())
+
+	res := ts.handler(ts.ctx, *msg)
+	require.True(t, res.IsOK(), res.Log)
+	require.Contains(t, res.Log, "is not allowed")
+	require.Equal(t, uint64(100000), res.GasUsed)
+
+	senderAcc := ts.ak.GetAccount(ts.ctx, sdk.AccAddress(sender.Bytes()))
+	require.Equal(t, uint64(1), senderAcc.GetSequence())
+	require.Equal(t, int64(1000000-100000), senderAcc.GetCoins().AmountOf(emint.DenomDefault).Int64())
+
+	stateDB, err := ts.keeper.NewCommitStateDB(ts.ctx)
+	require.NoError(t, err)
+	require.Zero(t, stateDB.GetCodeSize(ethcrypto.CreateAddress(sender, 0)))
+
+	// require a creation with init code holding a disallowed opcode to fail the
+	// same way
+	initCode = []byte{byte(ethvm.PUSH1), 0, byte(ethvm.SELFDESTRUCT)}
+
+	msg = types.NewEthereumTxMsgContract(1, big.NewInt(0), 100000, big.NewInt(1), initCode)
+	msg.Sign(big.NewInt(3), priv.ToECDSA())
+
+	res = ts.handler(ts.ctx, *msg)
+	require.True(t, res.IsOK(), res.Log)
+	require.Contains(t, res.Log, "invalid init code")
+
+	senderAcc = ts.ak.GetAccount(ts.ctx, sdk.AccAddress(sender.Bytes()))
+	require.Equal(t, uint64(2), senderAcc.GetSequence())
+	require.Equal(t, int64(1000000-2*100000), senderAcc.GetCoins().AmountOf(emint.DenomDefault).Int64())
+
+	// require the sender to be able to send its next transaction
+	msg = types.NewEthereumTxMsg(2, ethcmn.HexToAddress("0x1"), big.NewInt(100), 21000, big.NewInt(1), nil)
+	msg.Sign(big.NewInt(3), priv.ToECDSA())
+
+	res = ts.handler(ts.ctx, *msg)
+	require.True(t, res.IsOK(), res.Log)
+	require.Empty(t, res.Log)
+}
+
+func TestHandleUnknownMsg(t *testing.T) {
+	ts := newTestSetup(t, mockValidatorSet{})
+
+	res := ts.handler(ts.ctx, sdk.NewTestMsg())
+	require.Equal(t, sdk.CodeUnknownRequest, res.Code)
+}
+
+func TestGovHandler(t *testing.T) {
+	ts := newTestSetup(t, mockValidatorSet{})
+
+	govHandler := NewGovHandler(ts.keeper, func(_ sdk.Context, _ sdk.Msg) sdk.Result {
+		return sdk.Result{}
+	})
+
+	proposer := sdk.AccAddress(ethcmn.HexToAddress("0x1").Bytes())
+	deposit := sdk.Coins{sdk.NewInt64Coin("stake", 10)}
+
+	// require parameter change proposals to be rejected
+	msg := gov.NewMsgSubmitProposal("title", "description", gov.ProposalTypeParameterChange, proposer, deposit)
+	res := govHandler(ts.ctx, msg)
+	require.Equal(t, sdk.CodeUnknownRequest, res.Code)
+
+	msg = gov.NewMsgSubmitProposal("title", "description", gov.ProposalTypeText, proposer, deposit)
+	res = govHandler(ts.ctx, msg)
+	require.True(t, res.IsOK(), res.Log)
+
+	// require deposits of the EVM denomination to be rejected
+	evmDeposit := deposit.Plus(sdk.Coins{sdk.NewInt64Coin(emint.DenomDefault, 10)})
+
+	msg = gov.NewMsgSubmitProposal("title", "description", gov.ProposalTypeText, proposer, evmDeposit)
+	res = govHandler(ts.ctx, msg)
+	require.Equal(t, sdk.CodeInvalidCoins, res.Code)
+
+	res = govHandler(ts.ctx, gov.NewMsgDeposit(proposer, 1, evmDeposit))
+	require.Equal(t, sdk.CodeInvalidCoins, res.Code)
+
+	res = govHandler(ts.ctx, gov.NewMsgDeposit(proposer, 1, deposit))
+	require.True(t, res.IsOK(), res.Log)
+}
+
+func TestHandleSubmitParamsProposalMsg(t *testing.T) {
+	ts := newTestSetup(t, mockValidatorSet{})
+	proposer := sdk.AccAddress(ethcmn.HexToAddress("0x1").Bytes())
+
+	params := types.DefaultParams()
+	params.EVMDenom = "aphoton"
+	params.ChainConfig.PetersburgBlock = 100
+
+	// require a proposal without deposit to remain in its deposit period
+	msg := types.NewSubmitParamsProposalMsg("title", "description", params, proposer, nil)
+	require.NoError(t, msg.ValidateBasic())
+
+	res := ts.handler(ts.ctx, msg)
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, gov.StatusDepositPeriod, ts.gk.GetProposal(ts.ctx, 1).GetStatus())
+
+	stored, found := ts.keeper.GetParamsProposal(ts.ctx, 1)
+	require.True(t, found)
+	require.Equal(t, params, stored)
+
+	// require a proposal with deposit to enter its voting period
+	deposit := sdk.Coins{sdk.NewInt64Coin("stake", 10)}
+	msg = types.NewSubmitParamsProposalMsg("title", "description", params, proposer, deposit)
+
+	res = ts.handler(ts.ctx, msg)
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, gov.StatusVotingPeriod, ts.gk.GetProposal(ts.ctx, 2).GetStatus())
+
+	// require deposits of the EVM denomination to be rejected
+	evmDeposit := sdk.Coins{sdk.NewInt64Coin(emint.DenomDefault, 10)}
+	msg = types.NewSubmitParamsProposalMsg("title", "description", params, proposer, evmDeposit)
+
+	res = ts.handler(ts.ctx, msg)
+	require.Equal(t, sdk.CodeInvalidCoins, res.Code)
+
+	// require the bond or a deposit denomination to be rejected as the EVM
+	// denomination
+	params.EVMDenom = "stake"
+	msg = types.NewSubmitParamsProposalMsg("title", "description", params, proposer, deposit)
+	require.NoError(t, msg.ValidateBasic())
+
+	res = ts.handler(ts.ctx, msg)
+	require.Equal(t, sdk.CodeUnknownRequest, res.Code)
+
+	// require invalid proposed params to be rejected
+	params.MaxCodeSize = 0
+	msg = types.NewSubmitParamsProposalMsg("title", "description", params, proposer, deposit)
+	require.Error(t, msg.ValidateBasic())
+}
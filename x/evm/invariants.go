@@ -0,0 +1,53 @@
+package evm
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+)
+
+// Invariant defines a state invariant of the EVM module. It returns an error
+// if the invariant is broken.
+type Invariant func(ctx sdk.Context) error
+
+// SupplyInvariant returns an invariant checking that the total supply of the
+// EVM denomination, namely the balances of all the accounts and the collected
+// fees, equals the expected supply set in the EVM module store. The expected
+// supply is set to the current total supply if it has not been set yet or if
+// the EVM denomination has been changed.
+//
+// NOTE: Coins held by the stake and gov modules (e.g. bonded tokens and
+// proposal deposits) are not accounted for. This is enforced by
+// Keeper.ValidateParams and by rejecting deposits of the EVM denomination.
+func SupplyInvariant(k Keeper, fck FeeCollectionKeeper) Invariant {
+	return func(ctx sdk.Context) error {
+		supply := TotalSupply(ctx, k, fck)
+
+		expected, found := k.GetSupply(ctx)
+		if !found {
+			k.SetSupply(ctx, supply)
+			return nil
+		}
+
+		if supply.Denom != expected.Denom || !supply.IsEqual(expected) {
+			return fmt.Errorf("total supply is %s, expected %s", supply, expected)
+		}
+
+		return nil
+	}
+}
+
+// TotalSupply returns the total supply of the EVM denomination held by the
+// accounts and collected as fees.
+func TotalSupply(ctx sdk.Context, k Keeper, fck FeeCollectionKeeper) sdk.Coin {
+	denom := k.GetParams(ctx).EVMDenom
+	amount := fck.GetCollectedFees(ctx).AmountOf(denom)
+
+	k.ak.IterateAccounts(ctx, func(acc auth.Account) bool {
+		amount = amount.Add(acc.GetCoins().AmountOf(denom))
+		return false
+	})
+
+	return sdk.NewCoin(denom, amount)
+}
@@ -0,0 +1,70 @@
+package evm
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	emint "github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+type mockFeeCollectionKeeper sdk.Coins
+
+func (fck mockFeeCollectionKeeper) GetCollectedFees(_ sdk.Context) sdk.Coins {
+	return sdk.Coins(fck)
+}
+
+func TestSupplyInvariant(t *testing.T) {
+	ts := newTestSetup(t, mockValidatorSet{})
+	fck := mockFeeCollectionKeeper{sdk.NewInt64Coin(emint.DenomDefault, 10)}
+
+	setBalance := func(addr ethcmn.Address, coins sdk.Coins) {
+		acc := ts.ak.GetAccount(ts.ctx, sdk.AccAddress(addr.Bytes()))
+		if acc == nil {
+			acc = ts.ak.NewAccountWithAddress(ts.ctx, sdk.AccAddress(addr.Bytes()))
+		}
+
+		require.NoError(t, acc.SetCoins(coins))
+		ts.ak.SetAccount(ts.ctx, acc)
+	}
+
+	addr1 := ethcmn.HexToAddress("0x1")
+	addr2 := ethcmn.HexToAddress("0x2")
+
+	setBalance(addr1, sdk.Coins{sdk.NewInt64Coin(emint.DenomDefault, 100)})
+	setBalance(addr2, sdk.Coins{sdk.NewInt64Coin(emint.DenomDefault, 50), sdk.NewInt64Coin("aphoton", 5)})
+
+	require.Equal(t, sdk.NewInt64Coin(emint.DenomDefault, 160).String(), TotalSupply(ts.ctx, ts.keeper, fck).String())
+
+	// require the expected supply to be set when missing
+	invariant := SupplyInvariant(ts.keeper, fck)
+	require.NoError(t, invariant(ts.ctx))
+
+	supply, found := ts.keeper.GetSupply(ts.ctx)
+	require.True(t, found)
+	require.True(t, supply.IsEqual(sdk.NewInt64Coin(emint.DenomDefault, 160)))
+
+	// require transfers and other denominations not to break the invariant
+	setBalance(addr1, sdk.Coins{sdk.NewInt64Coin(emint.DenomDefault, 70)})
+	setBalance(addr2, sdk.Coins{sdk.NewInt64Coin(emint.DenomDefault, 80), sdk.NewInt64Coin("aphoton", 100)})
+	require.NoError(t, invariant(ts.ctx))
+
+	// require minted coins to break the invariant
+	setBalance(addr1, sdk.Coins{sdk.NewInt64Coin(emint.DenomDefault, 71)})
+	require.Error(t, invariant(ts.ctx))
+
+	// require a change of the EVM denomination not to reset the expected supply
+	setBalance(addr1, sdk.Coins{sdk.NewInt64Coin(emint.DenomDefault, 70)})
+	require.NoError(t, invariant(ts.ctx))
+
+	params := ts.keeper.GetParams(ts.ctx)
+	params.EVMDenom = "aphoton"
+	ts.keeper.SetParams(ts.ctx, params)
+	require.Error(t, invariant(ts.ctx))
+
+	supply, _ = ts.keeper.GetSupply(ts.ctx)
+	require.True(t, supply.IsEqual(sdk.NewInt64Coin(emint.DenomDefault, 160)))
+}
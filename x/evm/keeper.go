@@ -0,0 +1,216 @@
+package evm
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/params"
+
+	"github.com/cosmos/ethermint/x/evm/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Keeper defines the EVM module keeper. It gives access to the stores and
+// keepers needed to apply Ethereum transactions and to manage the EVM module
+// parameters.
+type Keeper struct {
+	cdc *codec.Codec
+
+	// EVM module store (e.g. pending parameter change proposals)
+	storeKey sdk.StoreKey
+
+	// contract storage, contract code and block header stores
+	storageKey sdk.StoreKey
+	codeKey    sdk.StoreKey
+	headerKey  sdk.StoreKey
+
+	ak         auth.AccountKeeper
+	gk         GovKeeper
+	vs         ValidatorSet
+	paramSpace params.Subspace
+}
+
+// NewKeeper returns a new EVM module keeper. The parameter subspace must have
+// the EVM module parameter type table set.
+func NewKeeper(
+	cdc *codec.Codec, storeKey, storageKey, codeKey, headerKey sdk.StoreKey,
+	ak auth.AccountKeeper, gk GovKeeper, vs ValidatorSet, paramSpace params.Subspace,
+) Keeper {
+
+	return Keeper{
+		cdc:        cdc,
+		storeKey:   storeKey,
+		storageKey: storageKey,
+		codeKey:    codeKey,
+		headerKey:  headerKey,
+		ak:         ak,
+		gk:         gk,
+		vs:         vs,
+		paramSpace: paramSpace,
+	}
+}
+
+// NewCommitStateDB returns a new CommitStateDB operating on the stores of the
+// given context.
+func (k Keeper) NewCommitStateDB(ctx sdk.Context) (*types.CommitStateDB, error) {
+	return types.NewCommitStateDB(ctx, k.ak, k.paramSpace, k.storageKey, k.codeKey)
+}
+
+// GetParams returns the EVM module parameters.
+func (k Keeper) GetParams(ctx sdk.Context) types.Params {
+	return types.GetParams(ctx, k.paramSpace)
+}
+
+// ValidateParams returns an error if the given EVM module parameters are
+// invalid. As coins held by the stake and gov modules are not accounted for in
+// the total supply (see SupplyInvariant), the EVM denomination must differ from
+// the bond denomination and from the denominations of the minimum deposit.
+func (k Keeper) ValidateParams(ctx sdk.Context, params types.Params) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	if params.EVMDenom == k.vs.BondDenom(ctx) {
+		return fmt.Errorf("EVM denomination cannot be the bond denomination: %s", params.EVMDenom)
+	}
+
+	for _, coin := range k.gk.GetDepositParams(ctx).MinDeposit {
+		if coin.Denom == params.EVMDenom {
+			return fmt.Errorf("EVM denomination cannot be a deposit denomination: %s", params.EVMDenom)
+		}
+	}
+
+	return nil
+}
+
+// SetParams sets the EVM module parameters.
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// GetSupply returns the expected total supply of the EVM denomination and
+// whether it has been set.
+func (k Keeper) GetSupply(ctx sdk.Context) (supply sdk.Coin, found bool) {
+	bz := ctx.KVStore(k.storeKey).Get(types.KeySupply)
+	if bz == nil {
+		return supply, false
+	}
+
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &supply)
+	return supply, true
+}
+
+// SetSupply sets the expected total supply of the EVM denomination.
+func (k Keeper) SetSupply(ctx sdk.Context, supply sdk.Coin) {
+	bz := k.cdc.MustMarshalBinaryLengthPrefixed(supply)
+	ctx.KVStore(k.storeKey).Set(types.KeySupply, bz)
+}
+
+// MigrateCodeRefs starts counting the references to the code persisted before
+// references were counted, unless it has already been done. It iterates over
+// all the accounts the first time it is called.
+func (k Keeper) MigrateCodeRefs(ctx sdk.Context) error {
+	store := ctx.KVStore(k.storeKey)
+	if store.Has(types.KeyCodeRefsMigrated) {
+		return nil
+	}
+
+	stateDB, err := k.NewCommitStateDB(ctx)
+	if err != nil {
+		return err
+	}
+
+	stateDB.MigrateCodeRefs()
+	k.SetCodeRefsMigrated(ctx)
+
+	return nil
+}
+
+// SetCodeRefsMigrated marks the references to all the persisted code as
+// counted (e.g. at genesis, as no code is persisted yet).
+func (k Keeper) SetCodeRefsMigrated(ctx sdk.Context) {
+	ctx.KVStore(k.storeKey).Set(types.KeyCodeRefsMigrated, []byte{1})
+}
+
+// ----------------------------------------------------------------------------
+// Receipts
+// ----------------------------------------------------------------------------
+
+// GetReceipt returns the receipt of the applied Ethereum transaction with the
+// given hash.
+func (k Keeper) GetReceipt(ctx sdk.Context, txHash ethcmn.Hash) (*ethtypes.Receipt, bool) {
+	bz := ctx.KVStore(k.storeKey).Get(types.ReceiptKey(txHash))
+	if bz == nil {
+		return nil, false
+	}
+
+	receipt := new(ethtypes.ReceiptForStorage)
+	if err := rlp.DecodeBytes(bz, receipt); err != nil {
+		panic(err)
+	}
+
+	return (*ethtypes.Receipt)(receipt), true
+}
+
+// SetReceipt sets the receipt of an applied Ethereum transaction.
+func (k Keeper) SetReceipt(ctx sdk.Context, receipt *ethtypes.Receipt) {
+	bz, err := rlp.EncodeToBytes((*ethtypes.ReceiptForStorage)(receipt))
+	if err != nil {
+		panic(err)
+	}
+
+	ctx.KVStore(k.storeKey).Set(types.ReceiptKey(receipt.TxHash), bz)
+}
+
+// ----------------------------------------------------------------------------
+// Parameter change proposals
+// ----------------------------------------------------------------------------
+
+// GetParamsProposal returns the parameters proposed by the pending parameter
+// change proposal with the given ID.
+func (k Keeper) GetParamsProposal(ctx sdk.Context, proposalID uint64) (params types.Params, found bool) {
+	bz := ctx.KVStore(k.storeKey).Get(types.ParamsProposalKey(proposalID))
+	if bz == nil {
+		return params, false
+	}
+
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &params)
+	return params, true
+}
+
+// SetParamsProposal sets the parameters proposed by the parameter change
+// proposal with the given ID.
+func (k Keeper) SetParamsProposal(ctx sdk.Context, proposalID uint64, params types.Params) {
+	bz := k.cdc.MustMarshalBinaryLengthPrefixed(params)
+	ctx.KVStore(k.storeKey).Set(types.ParamsProposalKey(proposalID), bz)
+}
+
+// DeleteParamsProposal deletes the parameters proposed by the parameter change
+// proposal with the given ID.
+func (k Keeper) DeleteParamsProposal(ctx sdk.Context, proposalID uint64) {
+	ctx.KVStore(k.storeKey).Delete(types.ParamsProposalKey(proposalID))
+}
+
+// IterateParamsProposals iterates over the pending parameter change proposals
+// in proposal ID order. The iteration stops when the callback returns true.
+func (k Keeper) IterateParamsProposals(ctx sdk.Context, cb func(proposalID uint64, params types.Params) (stop bool)) {
+	iter := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.KeyPrefixParamsProposal)
+	defer iter.Close()
+
+	for ; iter.Valid(); iter.Next() {
+		proposalID := binary.BigEndian.Uint64(iter.Key()[len(types.KeyPrefixParamsProposal):])
+
+		var params types.Params
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iter.Value(), &params)
+
+		if cb(proposalID, params) {
+			break
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package tags
+
+import (
+	"strings"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+)
+
+// EVM module tags. Addresses and hashes are tagged as lowercase 0x prefixed hex
+// strings so that transactions may be searched for by contract or event
+// signature (e.g. log-topic='0xddf2...').
+const (
+	Action                = "action"
+	Sender                = "sender"
+	Recipient             = "recipient"
+	ContractAddress       = "contract-address"
+	GasUsed               = "gas-used"
+	TxHash                = "tx-hash"
+	LogAddress            = "log-address"
+	LogTopic              = "log-topic"
+	Failed                = "failed"
+	ParamsProposalApplied = "evm-params-proposal-applied"
+
+	ActionEthereumTx = "ethereum-tx"
+)
+
+// Address returns the tag value of an Ethereum address.
+func Address(addr ethcmn.Address) []byte {
+	return []byte(strings.ToLower(addr.Hex()))
+}
+
+// Hash returns the tag value of a hash (e.g. transaction hash or log topic).
+func Hash(hash ethcmn.Hash) []byte {
+	return []byte(hash.Hex())
+}
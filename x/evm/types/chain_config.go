@@ -0,0 +1,137 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethparams "github.com/ethereum/go-ethereum/params"
+)
+
+// ChainConfig defines the Ethereum chain configuration (i.e. the hard fork
+// schedule) of the EVM. Activation heights refer to Ethermint block heights. A
+// negative height means the fork is not activated.
+type ChainConfig struct {
+	// ChainID defines the EIP-155 replay-protection chain ID. A zero value
+	// means the chain ID is parsed from the Tendermint chain ID.
+	ChainID int64 `json:"chain_id"`
+
+	HomesteadBlock int64 `json:"homestead_block"`
+
+	DAOForkBlock   int64 `json:"dao_fork_block"`
+	DAOForkSupport bool  `json:"dao_fork_support"`
+
+	EIP150Block         int64 `json:"eip150_block"`
+	EIP155Block         int64 `json:"eip155_block"`
+	EIP158Block         int64 `json:"eip158_block"`
+	ByzantiumBlock      int64 `json:"byzantium_block"`
+	ConstantinopleBlock int64 `json:"constantinople_block"`
+	PetersburgBlock     int64 `json:"petersburg_block"`
+}
+
+// DefaultChainConfig returns the default chain configuration, with all the hard
+// forks up to Petersburg activated from genesis and the DAO fork disabled.
+func DefaultChainConfig() ChainConfig {
+	return ChainConfig{
+		ChainID:             0,
+		HomesteadBlock:      0,
+		DAOForkBlock:        -1,
+		DAOForkSupport:      false,
+		EIP150Block:         0,
+		EIP155Block:         0,
+		EIP158Block:         0,
+		ByzantiumBlock:      0,
+		ConstantinopleBlock: 0,
+		PetersburgBlock:     0,
+	}
+}
+
+// Validate performs a basic validation of the chain configuration. Hard forks
+// must be activated in order, each at or after the height of the preceding
+// one.
+func (cc ChainConfig) Validate() error {
+	if cc.ChainID < 0 {
+		return fmt.Errorf("chain ID cannot be negative: %d", cc.ChainID)
+	}
+
+	if cc.DAOForkSupport && cc.DAOForkBlock < 0 {
+		return fmt.Errorf("DAO fork support requires a DAO fork block")
+	}
+
+	forks := []struct {
+		name   string
+		height int64
+	}{
+		{"homestead", cc.HomesteadBlock},
+		{"eip150", cc.EIP150Block},
+		{"eip155", cc.EIP155Block},
+		{"eip158", cc.EIP158Block},
+		{"byzantium", cc.ByzantiumBlock},
+		{"constantinople", cc.ConstantinopleBlock},
+		{"petersburg", cc.PetersburgBlock},
+	}
+
+	for i := 1; i < len(forks); i++ {
+		prev, cur := forks[i-1], forks[i]
+		if cur.height < 0 {
+			continue
+		}
+
+		if prev.height < 0 {
+			return fmt.Errorf("%s fork activated at %d without %s fork", cur.name, cur.height, prev.name)
+		}
+
+		if cur.height < prev.height {
+			return fmt.Errorf(
+				"%s fork activated at %d prior to %s fork at %d", cur.name, cur.height, prev.name, prev.height,
+			)
+		}
+	}
+
+	return nil
+}
+
+// EthereumConfig returns the Ethereum chain configuration used by the EVM with
+// the given EIP-155 chain ID.
+func (cc ChainConfig) EthereumConfig(chainID *big.Int) *ethparams.ChainConfig {
+	return &ethparams.ChainConfig{
+		ChainID:             chainID,
+		HomesteadBlock:      getBlockValue(cc.HomesteadBlock),
+		DAOForkBlock:        getBlockValue(cc.DAOForkBlock),
+		DAOForkSupport:      cc.DAOForkSupport,
+		EIP150Block:         getBlockValue(cc.EIP150Block),
+		EIP155Block:         getBlockValue(cc.EIP155Block),
+		EIP158Block:         getBlockValue(cc.EIP158Block),
+		ByzantiumBlock:      getBlockValue(cc.ByzantiumBlock),
+		ConstantinopleBlock: getBlockValue(cc.ConstantinopleBlock),
+		PetersburgBlock:     getBlockValue(cc.PetersburgBlock),
+	}
+}
+
+func (cc ChainConfig) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Chain Config:\n")
+	fmt.Fprintf(&b, "  Chain ID:              %d\n", cc.ChainID)
+	fmt.Fprintf(&b, "  Homestead Block:       %d\n", cc.HomesteadBlock)
+	fmt.Fprintf(&b, "  DAO Fork Block:        %d\n", cc.DAOForkBlock)
+	fmt.Fprintf(&b, "  DAO Fork Support:      %t\n", cc.DAOForkSupport)
+	fmt.Fprintf(&b, "  EIP150 Block:          %d\n", cc.EIP150Block)
+	fmt.Fprintf(&b, "  EIP155 Block:          %d\n", cc.EIP155Block)
+	fmt.Fprintf(&b, "  EIP158 Block:          %d\n", cc.EIP158Block)
+	fmt.Fprintf(&b, "  Byzantium Block:       %d\n", cc.ByzantiumBlock)
+	fmt.Fprintf(&b, "  Constantinople Block:  %d\n", cc.ConstantinopleBlock)
+	fmt.Fprintf(&b, "  Petersburg Block:      %d", cc.PetersburgBlock)
+
+	return b.String()
+}
+
+// getBlockValue returns the activation height of a hard fork as expected by
+// Ethereum's chain configuration, where nil means not activated.
+func getBlockValue(height int64) *big.Int {
+	if height < 0 {
+		return nil
+	}
+
+	return big.NewInt(height)
+}
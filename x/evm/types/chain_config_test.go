@@ -0,0 +1,56 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainConfigValidate(t *testing.T) {
+	testCases := []struct {
+		malleate   func(cc *ChainConfig)
+		expectPass bool
+	}{
+		{func(cc *ChainConfig) {}, true},
+		{func(cc *ChainConfig) { cc.ChainID = 8 }, true},
+		{func(cc *ChainConfig) { cc.ChainID = -1 }, false},
+		{func(cc *ChainConfig) { cc.DAOForkSupport = true }, false},
+		{func(cc *ChainConfig) { cc.DAOForkSupport, cc.DAOForkBlock = true, 5 }, true},
+		{func(cc *ChainConfig) { cc.ConstantinopleBlock, cc.PetersburgBlock = 10, 10 }, true},
+		{func(cc *ChainConfig) { cc.ConstantinopleBlock, cc.PetersburgBlock = 10, 5 }, false},
+		{func(cc *ChainConfig) { cc.ConstantinopleBlock, cc.PetersburgBlock = -1, -1 }, true},
+		{func(cc *ChainConfig) { cc.ConstantinopleBlock = -1 }, false},
+		{func(cc *ChainConfig) { cc.HomesteadBlock = 100 }, false},
+	}
+
+	for i, tc := range testCases {
+		cc := DefaultChainConfig()
+		tc.malleate(&cc)
+
+		err := cc.Validate()
+		if tc.expectPass {
+			require.NoError(t, err, "test case #%d", i)
+		} else {
+			require.Error(t, err, "test case #%d", i)
+		}
+	}
+}
+
+func TestChainConfigEthereumConfig(t *testing.T) {
+	cc := DefaultChainConfig()
+	cc.ByzantiumBlock = 5
+	cc.ConstantinopleBlock = 10
+	cc.PetersburgBlock = -1
+
+	ethConfig := cc.EthereumConfig(big.NewInt(3))
+	require.Equal(t, big.NewInt(3), ethConfig.ChainID)
+	require.Nil(t, ethConfig.DAOForkBlock)
+	require.Nil(t, ethConfig.PetersburgBlock)
+
+	require.True(t, ethConfig.IsHomestead(big.NewInt(0)))
+	require.False(t, ethConfig.IsByzantium(big.NewInt(4)))
+	require.True(t, ethConfig.IsByzantium(big.NewInt(5)))
+	require.False(t, ethConfig.IsConstantinople(big.NewInt(9)))
+	require.True(t, ethConfig.IsConstantinople(big.NewInt(10)))
+}
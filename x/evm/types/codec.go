@@ -16,4 +16,5 @@ func init() {
 // Register concrete types and interfaces on the given codec.
 func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(&EthereumTxMsg{}, "ethermint/MsgEthereumTx", nil)
+	cdc.RegisterConcrete(SubmitParamsProposalMsg{}, "ethermint/MsgSubmitParamsProposal", nil)
 }
@@ -1,12 +1,70 @@
 package types
 
 import (
+	"bytes"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
 	ethstate "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
-// RawDump returns a raw state dump.
+// RawDump returns a raw state dump of all the accounts in the account keeper
+// along with their code and storage. The dump is compatible with the one
+// produced by Geth: addresses, storage keys and code are hex encoded without a
+// 0x prefix, balances are decimal and storage values are RLP encoded.
 //
-// TODO: Implement if we need it, especially for the RPC API.
+// NOTE: Ethermint does not have a state trie, so the dump root is the
+// intermediate state root (see IntermediateRoot).
 func (csdb *CommitStateDB) RawDump() ethstate.Dump {
-	return ethstate.Dump{}
+	dump := ethstate.Dump{
+		Root:     fmt.Sprintf("%x", csdb.root),
+		Accounts: make(map[string]ethstate.DumpAccount),
+	}
+
+	// collect the addresses first so the account store iterator is closed
+	// before any state object is loaded
+	var addrs []ethcmn.Address
+	csdb.ak.IterateAccounts(csdb.ctx, func(acc auth.Account) bool {
+		addrs = append(addrs, ethcmn.BytesToAddress(acc.GetAddress().Bytes()))
+		return false
+	})
+
+	for _, addr := range addrs {
+		so := csdb.getStateObject(addr)
+		if so == nil {
+			continue
+		}
+
+		account := ethstate.DumpAccount{
+			Balance:  so.Balance().String(),
+			Nonce:    so.Nonce(),
+			Root:     ethcmn.Bytes2Hex(so.account.Root.Bytes()),
+			CodeHash: ethcmn.Bytes2Hex(so.CodeHash()),
+			Code:     ethcmn.Bytes2Hex(so.Code(nil)),
+			Storage:  make(map[string]string),
+		}
+
+		csdb.ForEachStorage(addr, func(key, value ethcmn.Hash) bool {
+			if (value == ethcmn.Hash{}) {
+				return true
+			}
+
+			// storage values are RLP encoded without leading zeros in Geth
+			bz, err := rlp.EncodeToBytes(bytes.TrimLeft(value.Bytes(), "\x00"))
+			if err != nil {
+				csdb.setError(err)
+				return false
+			}
+
+			account.Storage[ethcmn.Bytes2Hex(key.Bytes())] = ethcmn.Bytes2Hex(bz)
+			return true
+		})
+
+		dump.Accounts[ethcmn.Bytes2Hex(addr.Bytes())] = account
+	}
+
+	return dump
 }
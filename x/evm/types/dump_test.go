@@ -0,0 +1,48 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawDump(t *testing.T) {
+	ts := newTestSetup(t)
+
+	addr1 := GenerateEthAddress()
+	addr2 := GenerateEthAddress()
+	code := []byte("contract code")
+
+	ts.stateDB.AddBalance(addr1, big.NewInt(100))
+	ts.stateDB.SetNonce(addr1, 2)
+
+	ts.stateDB.SetNonce(addr2, 1)
+	ts.stateDB.SetCode(addr2, code)
+
+	root, err := ts.stateDB.Commit(true)
+	require.NoError(t, err)
+	require.NotEqual(t, ethcmn.Hash{}, root)
+
+	dump := ts.stateDB.RawDump()
+	require.NoError(t, ts.stateDB.Error())
+	require.Len(t, dump.Accounts, 2)
+	require.Equal(t, ethcmn.Bytes2Hex(root.Bytes()), dump.Root)
+
+	acc1, ok := dump.Accounts[ethcmn.Bytes2Hex(addr1.Bytes())]
+	require.True(t, ok)
+	require.Equal(t, "100", acc1.Balance)
+	require.Equal(t, uint64(2), acc1.Nonce)
+	require.Equal(t, ethcmn.Bytes2Hex(emptyCodeHash), acc1.CodeHash)
+	require.Empty(t, acc1.Code)
+	require.Empty(t, acc1.Storage)
+
+	acc2, ok := dump.Accounts[ethcmn.Bytes2Hex(addr2.Bytes())]
+	require.True(t, ok)
+	require.Equal(t, "0", acc2.Balance)
+	require.Equal(t, uint64(1), acc2.Nonce)
+	require.Equal(t, ethcmn.Bytes2Hex(ethcrypto.Keccak256(code)), acc2.CodeHash)
+	require.Equal(t, ethcmn.Bytes2Hex(code), acc2.Code)
+}
@@ -120,6 +120,10 @@ type (
 		hash ethcmn.Hash
 	}
 
+	codeErrorChange struct {
+		prev error
+	}
+
 	touchChange struct {
 		account   *ethcmn.Address
 		prev      bool
@@ -225,3 +229,11 @@ func (ch addPreimageChange) revert(s *CommitStateDB) {
 func (ch addPreimageChange) dirtied() *ethcmn.Address {
 	return nil
 }
+
+func (ch codeErrorChange) revert(s *CommitStateDB) {
+	s.codeErr = ch.prev
+}
+
+func (ch codeErrorChange) dirtied() *ethcmn.Address {
+	return nil
+}
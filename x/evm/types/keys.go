@@ -0,0 +1,94 @@
+package types
+
+import (
+	"encoding/binary"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// KeyPrefixCodeRefCount defines the prefix of the keys under which the number of
+// accounts referencing a contract code is persisted in the code store.
+var KeyPrefixCodeRefCount = []byte("refcount/")
+
+// KeyPrefixStorageMigrated defines the prefix of the keys marking the accounts
+// whose storage is no longer read under the legacy storage key layout.
+var KeyPrefixStorageMigrated = []byte("storagemigrated/")
+
+// KeyPrefixReceipt defines the prefix of the keys under which the receipts of
+// the applied Ethereum transactions are persisted in the EVM module store.
+var KeyPrefixReceipt = []byte("receipt/")
+
+// KeyPrefixParamsProposal defines the prefix of the keys under which the
+// parameters of pending parameter change proposals are persisted in the EVM
+// module store.
+var KeyPrefixParamsProposal = []byte("paramsproposal/")
+
+// KeySupply defines the key under which the expected total supply of the EVM
+// denomination is persisted in the EVM module store.
+var KeySupply = []byte("supply")
+
+// KeyCodeRefsMigrated defines the key marking, in the EVM module store, that the
+// references to all the persisted code are counted.
+var KeyCodeRefsMigrated = []byte("coderefsmigrated")
+
+// ParamsProposalKey returns the key under which the parameters of the parameter
+// change proposal with the given ID are persisted.
+func ParamsProposalKey(proposalID uint64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, proposalID)
+
+	return append(append([]byte{}, KeyPrefixParamsProposal...), bz...)
+}
+
+// AddressStoragePrefix returns the prefix of all the contract storage keys of
+// the given address.
+func AddressStoragePrefix(addr ethcmn.Address) []byte {
+	return addr.Bytes()
+}
+
+// StorageKey returns the key under which a contract storage slot is persisted.
+// It is the address of the contract followed by the slot key, so that all the
+// storage of a contract may be iterated over by its address prefix.
+func StorageKey(addr ethcmn.Address, key ethcmn.Hash) []byte {
+	prefix := AddressStoragePrefix(addr)
+	compositeKey := make([]byte, len(prefix)+ethcmn.HashLength)
+
+	copy(compositeKey, prefix)
+	copy(compositeKey[len(prefix):], key.Bytes())
+
+	return compositeKey
+}
+
+// LegacyStorageKey returns the key under which a contract storage slot was
+// persisted prior to the address prefixed layout, namely the hash of the
+// address followed by the slot key.
+//
+// CONTRACT: Entries stored under legacy keys are migrated to StorageKey when
+// they are read and the state object is committed. As the legacy keys cannot be
+// reversed, there is no migration of the entries never read: these are not
+// iterated over (e.g. by ForEachStorage and state dumps) and are no longer
+// reachable once the account is deleted (see StorageMigratedKey).
+func LegacyStorageKey(addr ethcmn.Address, key ethcmn.Hash) []byte {
+	return ethcrypto.Keccak256(addr.Bytes(), key.Bytes())
+}
+
+// ReceiptKey returns the key under which the receipt of the Ethereum
+// transaction with the given hash is persisted.
+func ReceiptKey(txHash ethcmn.Hash) []byte {
+	return append(append([]byte{}, KeyPrefixReceipt...), txHash.Bytes()...)
+}
+
+// StorageMigratedKey returns the key marking, in the contract storage store,
+// that the legacy storage key layout is no longer read for the given account
+// because the account has been deleted. Legacy entries of the account that
+// have never been read remain in the store.
+func StorageMigratedKey(addr ethcmn.Address) []byte {
+	return append(append([]byte{}, KeyPrefixStorageMigrated...), addr.Bytes()...)
+}
+
+// CodeRefCountKey returns the key under which the number of accounts referencing
+// the code with the given hash is persisted.
+func CodeRefCountKey(codeHash []byte) []byte {
+	return append(append([]byte{}, KeyPrefixCodeRefCount...), codeHash...)
+}
@@ -0,0 +1,138 @@
+package types
+
+import (
+	"bytes"
+	"testing"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageKey(t *testing.T) {
+	addr := GenerateEthAddress()
+	key := ethcmn.BytesToHash([]byte{0x1})
+
+	storageKey := StorageKey(addr, key)
+	require.Len(t, storageKey, ethcmn.AddressLength+ethcmn.HashLength)
+	require.True(t, bytes.HasPrefix(storageKey, AddressStoragePrefix(addr)))
+	require.Equal(t, key.Bytes(), storageKey[ethcmn.AddressLength:])
+
+	legacyKey := LegacyStorageKey(addr, key)
+	require.Equal(t, ethcrypto.Keccak256(append(addr.Bytes(), key.Bytes()...)), legacyKey)
+}
+
+func TestForEachStorage(t *testing.T) {
+	ts := newTestSetup(t)
+
+	addr1 := GenerateEthAddress()
+	addr2 := GenerateEthAddress()
+
+	expected := map[ethcmn.Hash]ethcmn.Hash{
+		ethcmn.BytesToHash([]byte{0x1}): ethcmn.BytesToHash([]byte("value1")),
+		ethcmn.BytesToHash([]byte{0x2}): ethcmn.BytesToHash([]byte("value2")),
+		ethcmn.BytesToHash([]byte{0x3}): ethcmn.BytesToHash([]byte("value3")),
+	}
+
+	for key, value := range expected {
+		ts.stateDB.SetState(addr1, key, value)
+	}
+
+	ts.stateDB.SetState(addr2, ethcmn.BytesToHash([]byte{0x1}), ethcmn.BytesToHash([]byte("other")))
+	ts.stateDB.Finalize(false)
+
+	// require a pending value to take precedence over the committed one
+	dirtyKey := ethcmn.BytesToHash([]byte{0x2})
+	expected[dirtyKey] = ethcmn.BytesToHash([]byte("dirty"))
+	ts.stateDB.SetState(addr1, dirtyKey, expected[dirtyKey])
+
+	storage := make(map[ethcmn.Hash]ethcmn.Hash)
+	ts.stateDB.ForEachStorage(addr1, func(key, value ethcmn.Hash) bool {
+		storage[key] = value
+		return true
+	})
+
+	require.Equal(t, expected, storage)
+
+	// require the iteration to stop when the callback returns false
+	count := 0
+	ts.stateDB.ForEachStorage(addr1, func(key, value ethcmn.Hash) bool {
+		count++
+		return false
+	})
+
+	require.Equal(t, 1, count)
+}
+
+func TestLegacyStorageMigration(t *testing.T) {
+	ts := newTestSetup(t)
+	store := ts.ctx.KVStore(ts.storageKey)
+
+	addr := GenerateEthAddress()
+	ts.stateDB.CreateAccount(addr)
+
+	key1 := ethcmn.BytesToHash([]byte{0x1})
+	key2 := ethcmn.BytesToHash([]byte{0x2})
+	key3 := ethcmn.BytesToHash([]byte{0x3})
+	value := ethcmn.BytesToHash([]byte("value"))
+
+	for _, key := range []ethcmn.Hash{key1, key2, key3} {
+		store.Set(LegacyStorageKey(addr, key), value.Bytes())
+	}
+
+	// require legacy entries to be readable
+	require.Equal(t, value, ts.stateDB.GetState(addr, key1))
+	require.Equal(t, value, ts.stateDB.GetCommittedState(addr, key2))
+
+	// require a legacy entry overwritten with its own value and a deleted one
+	// to be migrated as well
+	ts.stateDB.SetState(addr, key3, ethcmn.Hash{})
+	ts.stateDB.SetState(addr, key2, value)
+	ts.stateDB.Finalize(false)
+
+	for _, key := range []ethcmn.Hash{key1, key2, key3} {
+		require.Nil(t, store.Get(LegacyStorageKey(addr, key)))
+	}
+
+	require.Equal(t, value.Bytes(), store.Get(StorageKey(addr, key1)))
+	require.Equal(t, value.Bytes(), store.Get(StorageKey(addr, key2)))
+	require.Nil(t, store.Get(StorageKey(addr, key3)))
+
+	// require the migrated entries to be read by a new state object
+	require.NoError(t, ts.stateDB.Reset(ethcmn.Hash{}))
+	require.Equal(t, value, ts.stateDB.GetState(addr, key1))
+	require.Equal(t, ethcmn.Hash{}, ts.stateDB.GetState(addr, key3))
+}
+
+func TestLegacyStorageDeleted(t *testing.T) {
+	ts := newTestSetup(t)
+	store := ts.ctx.KVStore(ts.storageKey)
+
+	addr := GenerateEthAddress()
+	key := ethcmn.BytesToHash([]byte{0x1})
+	value := ethcmn.BytesToHash([]byte("value"))
+
+	ts.stateDB.SetCode(addr, []byte("contract code"))
+	ts.stateDB.SetNonce(addr, 1)
+	_, err := ts.stateDB.Commit(true)
+	require.NoError(t, err)
+
+	store.Set(LegacyStorageKey(addr, key), value.Bytes())
+
+	// require an unread legacy entry of a destroyed contract not to be read
+	// once the contract is re-created at the same address
+	require.NoError(t, ts.stateDB.Reset(ethcmn.Hash{}))
+	require.True(t, ts.stateDB.Suicide(addr))
+	_, err = ts.stateDB.Commit(true)
+	require.NoError(t, err)
+
+	ts.stateDB.CreateAccount(addr)
+	ts.stateDB.SetCode(addr, []byte("other contract code"))
+	require.Equal(t, ethcmn.Hash{}, ts.stateDB.GetState(addr, key))
+
+	_, err = ts.stateDB.Commit(true)
+	require.NoError(t, err)
+
+	require.NoError(t, ts.stateDB.Reset(ethcmn.Hash{}))
+	require.Equal(t, ethcmn.Hash{}, ts.stateDB.GetState(addr, key))
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/ethermint/crypto"
 	"github.com/cosmos/ethermint/types"
 
 	ethcmn "github.com/ethereum/go-ethereum/common"
@@ -318,39 +319,33 @@ func TxDecoder(cdc *codec.Codec) sdk.TxDecoder {
 }
 
 // recoverEthSig recovers a signature according to the Ethereum specification and
-// returns the sender or an error.
+// returns the sender or an error. The signature must abide by the same strict
+// rules as any other signature in Ethermint (see crypto.ValidateSignature).
 //
 // Ref: Ethereum Yellow Paper (BYZANTIUM VERSION 69351d5) Appendix F
 func recoverEthSig(R, S, Vb *big.Int, sigHash ethcmn.Hash) (ethcmn.Address, error) {
-	if Vb.BitLen() > 8 {
+	if Vb.Sign() < 0 || Vb.BitLen() > 8 || R.BitLen() > 256 || S.BitLen() > 256 {
 		return ethcmn.Address{}, errors.New("invalid signature")
 	}
 
-	V := byte(Vb.Uint64() - 27)
-	if !ethcrypto.ValidateSignatureValues(V, R, S, true) {
+	V := Vb.Uint64() - 27
+	if V > 1 {
 		return ethcmn.Address{}, errors.New("invalid signature")
 	}
 
 	// encode the signature in uncompressed format
 	r, s := R.Bytes(), S.Bytes()
-	sig := make([]byte, 65)
+	sig := make([]byte, crypto.SignatureLength)
 
 	copy(sig[32-len(r):32], r)
 	copy(sig[64-len(s):64], s)
-	sig[64] = V
+	sig[64] = byte(V)
 
 	// recover the public key from the signature
-	pub, err := ethcrypto.Ecrecover(sigHash[:], sig)
+	pubKey, err := crypto.RecoverPubKey(sigHash[:], sig)
 	if err != nil {
 		return ethcmn.Address{}, err
 	}
 
-	if len(pub) == 0 || pub[0] != 4 {
-		return ethcmn.Address{}, errors.New("invalid public key")
-	}
-
-	var addr ethcmn.Address
-	copy(addr[:], ethcrypto.Keccak256(pub[1:])[12:])
-
-	return addr, nil
+	return ethcmn.BytesToAddress(pubKey.Address().Bytes()), nil
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/cosmos/ethermint/crypto"
 	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/require"
 )
@@ -118,6 +119,21 @@ func TestMsgEthereumTxSig(t *testing.T) {
 	signer, err = msg.VerifySig(big.NewInt(4))
 	require.Error(t, err)
 	require.Equal(t, ethcmn.Address{}, signer)
+
+	// require malleated (high-S) signature fail validation
+	msg = NewEthereumTxMsg(0, addr1, nil, 100000, nil, []byte("test"))
+	msg.Sign(chainID, priv1.ToECDSA())
+
+	chainIDMul := new(big.Int).Mul(chainID, big.NewInt(2))
+	recID := new(big.Int).Sub(msg.Data.V, chainIDMul)
+	recID.Sub(recID, big.NewInt(35))
+
+	msg.Data.S = new(big.Int).Sub(ethcrypto.S256().Params().N, msg.Data.S)
+	msg.Data.V = new(big.Int).Add(chainIDMul, big.NewInt(35+1-recID.Int64()))
+
+	signer, err = msg.VerifySig(chainID)
+	require.Error(t, err)
+	require.Equal(t, ethcmn.Address{}, signer)
 }
 
 func TestMsgEthereumTxAmino(t *testing.T) {
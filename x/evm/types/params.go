@@ -0,0 +1,332 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"regexp"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/params"
+
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethparams "github.com/ethereum/go-ethereum/params"
+)
+
+// DefaultParamspace defines the default EVM module parameter subspace.
+const DefaultParamspace = "evm"
+
+// Parameter store keys
+var (
+	ParamStoreKeyEVMDenom         = []byte("evmdenom")
+	ParamStoreKeyChainConfig      = []byte("chainconfig")
+	ParamStoreKeyMaxCodeSize      = []byte("maxcodesize")
+	ParamStoreKeyAllowedOpcodes   = []byte("allowedopcodes")
+	ParamStoreKeyAllowedDeployers = []byte("alloweddeployers")
+	ParamStoreKeyAllowedCallers   = []byte("allowedcallers")
+)
+
+// reDenom matches a valid coin denomination as accepted by the SDK.
+var reDenom = regexp.MustCompile(`^[[:alpha:]][[:alnum:]]{2,15}$`)
+
+var _ params.ParamSet = (*Params)(nil)
+
+// Params defines the EVM module parameters.
+type Params struct {
+	// EVMDenom defines the coin denomination used for EVM balances, value
+	// transfers and gas payments.
+	EVMDenom string `json:"evm_denom"`
+
+	// ChainConfig defines the Ethereum chain configuration (EIP-155 chain ID
+	// and hard fork schedule) the EVM runs with.
+	ChainConfig ChainConfig `json:"chain_config"`
+
+	// MaxCodeSize defines the maximum size in bytes of contract code. It cannot
+	// exceed the limit of 24576 bytes the EVM enforces once EIP-158 is
+	// activated, as a larger value would have no effect.
+	MaxCodeSize uint64 `json:"max_code_size"`
+
+	// AllowedOpcodes defines the names of the opcodes contract code may
+	// contain (e.g. "SELFDESTRUCT"). An empty list allows every opcode. The
+	// runtime code of every created contract and the init code of contract
+	// creation transactions are validated. The init code of contracts created
+	// by other contracts is not, so CREATE and CREATE2 must not be allowed for
+	// the list to be enforced on all code. See ValidateOpcodes for how data
+	// appended to the code is handled.
+	AllowedOpcodes []string `json:"allowed_opcodes"`
+
+	// AllowedDeployers defines the hex addresses of the accounts allowed to
+	// deploy contracts through a transaction. An empty list allows every
+	// account.
+	AllowedDeployers []string `json:"allowed_deployers"`
+
+	// AllowedCallers defines the hex addresses of the accounts allowed to call
+	// contracts through a transaction. An empty list allows every account.
+	// Calls made by contracts are not restricted.
+	AllowedCallers []string `json:"allowed_callers"`
+}
+
+// ParamTypeTable returns the type table for the EVM module parameters.
+func ParamTypeTable() params.TypeTable {
+	return params.NewTypeTable().RegisterParamSet(&Params{})
+}
+
+// DefaultParams returns the default EVM module parameters.
+func DefaultParams() Params {
+	return Params{
+		EVMDenom:    types.DenomDefault,
+		ChainConfig: DefaultChainConfig(),
+		MaxCodeSize: ethparams.MaxCodeSize,
+	}
+}
+
+// KeyValuePairs implements the params.ParamSet interface.
+func (p *Params) KeyValuePairs() params.KeyValuePairs {
+	return params.KeyValuePairs{
+		{Key: ParamStoreKeyEVMDenom, Value: &p.EVMDenom},
+		{Key: ParamStoreKeyChainConfig, Value: &p.ChainConfig},
+		{Key: ParamStoreKeyMaxCodeSize, Value: &p.MaxCodeSize},
+		{Key: ParamStoreKeyAllowedOpcodes, Value: &p.AllowedOpcodes},
+		{Key: ParamStoreKeyAllowedDeployers, Value: &p.AllowedDeployers},
+		{Key: ParamStoreKeyAllowedCallers, Value: &p.AllowedCallers},
+	}
+}
+
+// Validate performs a basic validation of the EVM module parameters.
+func (p Params) Validate() error {
+	if err := validateDenom(p.EVMDenom); err != nil {
+		return fmt.Errorf("invalid EVM denomination: %s", err)
+	}
+
+	if err := p.ChainConfig.Validate(); err != nil {
+		return fmt.Errorf("invalid chain config: %s", err)
+	}
+
+	if p.MaxCodeSize == 0 || p.MaxCodeSize > ethparams.MaxCodeSize {
+		return fmt.Errorf("max code size must be between 1 and %d: %d", ethparams.MaxCodeSize, p.MaxCodeSize)
+	}
+
+	for _, name := range p.AllowedOpcodes {
+		if _, err := parseOpcode(name); err != nil {
+			return err
+		}
+	}
+
+	for _, allowList := range [][]string{p.AllowedDeployers, p.AllowedCallers} {
+		for _, addr := range allowList {
+			if !ethcmn.IsHexAddress(addr) {
+				return fmt.Errorf("invalid allowed address %q", addr)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateSender validates that the sender of a transaction is allowed to
+// deploy a contract (contract creation) or to call one (contract call) as set
+// in the allow-lists. Value transfers to accounts without code are always
+// allowed.
+func (p Params) ValidateSender(sender ethcmn.Address, contractCreation, contractCall bool) error {
+	if contractCreation && !isAllowed(p.AllowedDeployers, sender) {
+		return fmt.Errorf("%s is not allowed to deploy contracts", sender.Hex())
+	}
+
+	if contractCall && !isAllowed(p.AllowedCallers, sender) {
+		return fmt.Errorf("%s is not allowed to call contracts", sender.Hex())
+	}
+
+	return nil
+}
+
+// ValidateCode validates the runtime code of a contract against the maximum
+// code size and the allowed opcodes.
+func (p Params) ValidateCode(code []byte) error {
+	if uint64(len(code)) > p.MaxCodeSize {
+		return fmt.Errorf("code size %d exceeds the maximum of %d", len(code), p.MaxCodeSize)
+	}
+
+	return p.ValidateOpcodes(code)
+}
+
+// ValidateOpcodes validates that contract code (runtime or init code) only
+// contains allowed opcodes. The designated invalid opcode, which only aborts
+// the execution, is always allowed. Push data is skipped, and so is the
+// metadata trailer appended by the Solidity compiler if it cannot be executed
+// (see metadataLen). Any other data appended to the code (e.g. the code of the
+// contracts it creates) is validated as code.
+func (p Params) ValidateOpcodes(code []byte) error {
+	if len(p.AllowedOpcodes) == 0 {
+		return nil
+	}
+
+	allowed := make(map[ethvm.OpCode]bool, len(p.AllowedOpcodes))
+	for _, name := range p.AllowedOpcodes {
+		op, err := parseOpcode(name)
+		if err != nil {
+			return err
+		}
+
+		allowed[op] = true
+	}
+
+	end := len(code) - metadataLen(code)
+	for i := 0; i < end; i++ {
+		op := ethvm.OpCode(code[i])
+		if !allowed[op] && op != opInvalid {
+			return fmt.Errorf("opcode %s at %d is not allowed", op, i)
+		}
+
+		if op.IsPush() {
+			i += int(op - ethvm.PUSH1 + 1)
+		}
+	}
+
+	return nil
+}
+
+// opInvalid is the designated invalid opcode (EIP-141), which the Solidity
+// compiler places between the code and the metadata trailer.
+const opInvalid ethvm.OpCode = 0xfe
+
+// metadataLen returns the length of the metadata trailer appended to contract
+// code by the Solidity compiler, namely a CBOR map followed by its length as a
+// 2-byte big-endian integer. Zero is returned if the code has no such trailer
+// or if the trailer could be executed, i.e. if it does not follow an opcode
+// halting the execution or if it holds a jump destination.
+func metadataLen(code []byte) int {
+	if len(code) < 3 {
+		return 0
+	}
+
+	n := int(binary.BigEndian.Uint16(code[len(code)-2:])) + 2
+	start := len(code) - n
+
+	// the trailer must start with a CBOR map (major type 5)
+	if start < 1 || code[start]&0xe0 != 0xa0 {
+		return 0
+	}
+
+	last := -1
+	for i := 0; i < len(code); i++ {
+		op := ethvm.OpCode(code[i])
+		if i >= start && op == ethvm.JUMPDEST {
+			return 0
+		}
+
+		if i < start {
+			last = i
+		}
+
+		if op.IsPush() {
+			i += int(op - ethvm.PUSH1 + 1)
+		}
+	}
+
+	// the last instruction of the code must end right before the trailer
+	if last != start-1 {
+		return 0
+	}
+
+	switch ethvm.OpCode(code[last]) {
+	case ethvm.STOP, ethvm.JUMP, ethvm.RETURN, ethvm.REVERT, opInvalid, ethvm.SELFDESTRUCT:
+		return n
+
+	default:
+		return 0
+	}
+}
+
+func (p Params) String() string {
+	return fmt.Sprintf(`EVM Params:
+  EVM Denom:         %s
+  Max Code Size:     %d
+  Allowed Opcodes:   %v
+  Allowed Deployers: %v
+  Allowed Callers:   %v
+%s`,
+		p.EVMDenom, p.MaxCodeSize, p.AllowedOpcodes, p.AllowedDeployers, p.AllowedCallers, p.ChainConfig,
+	)
+}
+
+// GetParams returns the EVM module parameters set in the given parameter
+// subspace. Default values are used for the parameters that are not set.
+func GetParams(ctx sdk.Context, paramSpace params.Subspace) Params {
+	p := DefaultParams()
+	for _, pair := range p.KeyValuePairs() {
+		paramSpace.GetIfExists(ctx, pair.Key, pair.Value)
+	}
+
+	return p
+}
+
+// GetEVMDenom returns the EVM denomination set in the given parameter subspace.
+// The default denomination is returned if the parameter is not set (e.g. prior
+// to genesis).
+func GetEVMDenom(ctx sdk.Context, paramSpace params.Subspace) string {
+	denom := types.DenomDefault
+	paramSpace.GetIfExists(ctx, ParamStoreKeyEVMDenom, &denom)
+
+	return denom
+}
+
+// GetChainConfig returns the chain configuration set in the given parameter
+// subspace. The default configuration is returned if the parameter is not set.
+func GetChainConfig(ctx sdk.Context, paramSpace params.Subspace) ChainConfig {
+	chainConfig := DefaultChainConfig()
+	paramSpace.GetIfExists(ctx, ParamStoreKeyChainConfig, &chainConfig)
+
+	return chainConfig
+}
+
+// GetChainID returns the EIP-155 chain ID used to sign and verify Ethereum
+// transactions. It is the chain ID set in the chain configuration or, if not
+// set, the Tendermint chain ID which must then be a base-10 integer.
+func GetChainID(ctx sdk.Context, chainConfig ChainConfig) (*big.Int, error) {
+	if chainConfig.ChainID > 0 {
+		return big.NewInt(chainConfig.ChainID), nil
+	}
+
+	chainID, ok := new(big.Int).SetString(ctx.ChainID(), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid chainID: %s", ctx.ChainID())
+	}
+
+	return chainID, nil
+}
+
+// parseOpcode returns the opcode of the given name.
+func parseOpcode(name string) (ethvm.OpCode, error) {
+	op := ethvm.StringToOp(name)
+	if op == ethvm.STOP && name != ethvm.STOP.String() {
+		return 0, fmt.Errorf("invalid opcode %q", name)
+	}
+
+	return op, nil
+}
+
+// isAllowed returns true if the allow-list is empty or contains the address.
+func isAllowed(allowList []string, addr ethcmn.Address) bool {
+	if len(allowList) == 0 {
+		return true
+	}
+
+	for _, allowed := range allowList {
+		if ethcmn.HexToAddress(allowed) == addr {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateDenom(denom string) error {
+	if !reDenom.MatchString(denom) {
+		return fmt.Errorf("%q must be 3 to 16 alphanumeric characters starting with a letter", denom)
+	}
+
+	return nil
+}
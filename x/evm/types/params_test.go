@@ -0,0 +1,166 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethvm "github.com/ethereum/go-ethereum/core/vm"
+	ethparams "github.com/ethereum/go-ethereum/params"
+)
+
+func TestParamsValidate(t *testing.T) {
+	testCases := []struct {
+		malleate   func(p *Params)
+		expectPass bool
+	}{
+		{func(p *Params) {}, true},
+		{func(p *Params) { p.EVMDenom = "aphoton" }, true},
+		{func(p *Params) { p.EVMDenom = "" }, false},
+		{func(p *Params) { p.EVMDenom = "1photon" }, false},
+		{func(p *Params) { p.EVMDenom = "pho ton" }, false},
+		{func(p *Params) { p.ChainConfig.ChainID = -1 }, false},
+		{func(p *Params) { p.MaxCodeSize = 0 }, false},
+		{func(p *Params) { p.MaxCodeSize = ethparams.MaxCodeSize + 1 }, false},
+		{func(p *Params) { p.AllowedOpcodes = []string{"STOP", "PUSH1", "SSTORE"} }, true},
+		{func(p *Params) { p.AllowedOpcodes = []string{"PUSH1", "FOO"} }, false},
+		{func(p *Params) { p.AllowedDeployers = []string{"0x0000000000000000000000000000000000000001"} }, true},
+		{func(p *Params) { p.AllowedDeployers = []string{"0x1"} }, false},
+		{func(p *Params) { p.AllowedCallers = []string{"0x0000000000000000000000000000000000000001"} }, true},
+		{func(p *Params) { p.AllowedCallers = []string{"cosmos1qyqszqgpqyqszqgpqyqszqgpqyqszqgpjnp7du"} }, false},
+	}
+
+	for i, tc := range testCases {
+		params := DefaultParams()
+		tc.malleate(&params)
+
+		err := params.Validate()
+		if tc.expectPass {
+			require.NoError(t, err, "test case #%d", i)
+		} else {
+			require.Error(t, err, "test case #%d", i)
+		}
+	}
+
+	require.Equal(t, types.DenomDefault, DefaultParams().EVMDenom)
+}
+
+func TestParamsValidateCode(t *testing.T) {
+	code := []byte{
+		byte(ethvm.PUSH1), byte(ethvm.SELFDESTRUCT),
+		byte(ethvm.PUSH1), 0x0,
+		byte(ethvm.SSTORE),
+	}
+
+	testCases := []struct {
+		malleate   func(p *Params)
+		expectPass bool
+	}{
+		{func(p *Params) {}, true},
+		{func(p *Params) { p.MaxCodeSize = uint64(len(code)) }, true},
+		{func(p *Params) { p.MaxCodeSize = uint64(len(code) - 1) }, false},
+		// push data must not be mistaken for opcodes
+		{func(p *Params) { p.AllowedOpcodes = []string{"PUSH1", "SSTORE"} }, true},
+		{func(p *Params) { p.AllowedOpcodes = []string{"PUSH1"} }, false},
+		{func(p *Params) { p.AllowedOpcodes = []string{"SSTORE"} }, false},
+	}
+
+	for i, tc := range testCases {
+		params := DefaultParams()
+		tc.malleate(&params)
+
+		err := params.ValidateCode(code)
+		if tc.expectPass {
+			require.NoError(t, err, "test case #%d", i)
+		} else {
+			require.Error(t, err, "test case #%d", i)
+		}
+	}
+}
+
+func TestParamsValidateOpcodesMetadata(t *testing.T) {
+	code := []byte{byte(ethvm.PUSH1), 0x1, byte(ethvm.PUSH1), 0x0, byte(ethvm.SSTORE)}
+
+	// metadataTrailer returns a CBOR map {"solc": value} followed by its length
+	metadataTrailer := func(value ...byte) []byte {
+		trailer := append([]byte{0xa1, 0x64, 's', 'o', 'l', 'c', 0x40 + byte(len(value))}, value...)
+		return append(trailer, 0x0, byte(len(trailer)))
+	}
+
+	concat := func(parts ...[]byte) []byte {
+		var bz []byte
+		for _, part := range parts {
+			bz = append(bz, part...)
+		}
+
+		return bz
+	}
+
+	testCases := []struct {
+		code       []byte
+		expectPass bool
+	}{
+		{concat(code, []byte{byte(ethvm.STOP)}), true},
+		{concat(code, []byte{byte(ethvm.STOP)}, metadataTrailer(0x0, 0x5, byte(ethvm.SELFDESTRUCT))), true},
+		{concat(code, []byte{byte(opInvalid)}, metadataTrailer(0x0, 0x5, byte(ethvm.SELFDESTRUCT))), true},
+		// the trailer must not be reachable by falling through the code
+		{concat(code, metadataTrailer(0x0, 0x5, byte(ethvm.SELFDESTRUCT))), false},
+		// the trailer must not hold a jump destination
+		{concat(code, []byte{byte(ethvm.STOP)}, metadataTrailer(byte(ethvm.JUMPDEST), byte(ethvm.SELFDESTRUCT))), false},
+		// the trailer length must frame a CBOR map
+		{concat(code, []byte{byte(ethvm.STOP), byte(ethvm.SELFDESTRUCT), 0x0, 0x1}), false},
+	}
+
+	params := DefaultParams()
+	params.AllowedOpcodes = []string{"PUSH1", "SSTORE", "STOP"}
+
+	for i, tc := range testCases {
+		err := params.ValidateOpcodes(tc.code)
+		if tc.expectPass {
+			require.NoError(t, err, "test case #%d", i)
+		} else {
+			require.Error(t, err, "test case #%d", i)
+		}
+	}
+}
+
+func TestParamsValidateSender(t *testing.T) {
+	sender := ethcmn.HexToAddress("0x1")
+	other := ethcmn.HexToAddress("0x2")
+
+	testCases := []struct {
+		malleate         func(p *Params)
+		contractCreation bool
+		contractCall     bool
+		expectPass       bool
+	}{
+		{func(p *Params) {}, true, false, true},
+		{func(p *Params) {}, false, true, true},
+		{func(p *Params) { p.AllowedDeployers = []string{other.Hex()} }, true, false, false},
+		{func(p *Params) { p.AllowedDeployers = []string{other.Hex(), sender.Hex()} }, true, false, true},
+		{func(p *Params) { p.AllowedDeployers = []string{other.Hex()} }, false, true, true},
+		{func(p *Params) { p.AllowedCallers = []string{other.Hex()} }, false, true, false},
+		{func(p *Params) { p.AllowedCallers = []string{sender.Hex()} }, false, true, true},
+		{func(p *Params) { p.AllowedCallers = []string{other.Hex()} }, true, false, true},
+		// value transfers are never restricted
+		{func(p *Params) {
+			p.AllowedDeployers = []string{other.Hex()}
+			p.AllowedCallers = []string{other.Hex()}
+		}, false, false, true},
+	}
+
+	for i, tc := range testCases {
+		params := DefaultParams()
+		tc.malleate(&params)
+
+		err := params.ValidateSender(sender, tc.contractCreation, tc.contractCall)
+		if tc.expectPass {
+			require.NoError(t, err, "test case #%d", i)
+		} else {
+			require.Error(t, err, "test case #%d", i)
+		}
+	}
+}
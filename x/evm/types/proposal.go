@@ -0,0 +1,82 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// TypeSubmitParamsProposalMsg defines the message type of a parameter change
+// proposal submission.
+const TypeSubmitParamsProposalMsg = "submit_params_proposal"
+
+var _ sdk.Msg = SubmitParamsProposalMsg{}
+
+// SubmitParamsProposalMsg submits a governance proposal to change the EVM
+// module parameters (e.g. EVM denomination, hard fork schedule, maximum code
+// size and allowed opcodes). The proposal goes through the regular deposit and
+// voting periods and the parameters are set once it passes.
+type SubmitParamsProposalMsg struct {
+	Title          string         `json:"title"`
+	Description    string         `json:"description"`
+	Params         Params         `json:"params"`
+	Proposer       sdk.AccAddress `json:"proposer"`
+	InitialDeposit sdk.Coins      `json:"initial_deposit"`
+}
+
+// NewSubmitParamsProposalMsg returns a new parameter change proposal message.
+func NewSubmitParamsProposalMsg(
+	title, description string, params Params, proposer sdk.AccAddress, initialDeposit sdk.Coins,
+) SubmitParamsProposalMsg {
+
+	return SubmitParamsProposalMsg{
+		Title:          title,
+		Description:    description,
+		Params:         params,
+		Proposer:       proposer,
+		InitialDeposit: initialDeposit,
+	}
+}
+
+// Route returns the route value of a SubmitParamsProposalMsg.
+func (msg SubmitParamsProposalMsg) Route() string { return RouteEthereumTxMsg }
+
+// Type returns the type value of a SubmitParamsProposalMsg.
+func (msg SubmitParamsProposalMsg) Type() string { return TypeSubmitParamsProposalMsg }
+
+// ValidateBasic implements the sdk.Msg interface. It validates the proposal
+// content, the proposed parameters and the initial deposit.
+func (msg SubmitParamsProposalMsg) ValidateBasic() sdk.Error {
+	if len(strings.TrimSpace(msg.Title)) == 0 {
+		return sdk.ErrUnknownRequest("proposal title cannot be blank")
+	}
+
+	if len(strings.TrimSpace(msg.Description)) == 0 {
+		return sdk.ErrUnknownRequest("proposal description cannot be blank")
+	}
+
+	if err := msg.Params.Validate(); err != nil {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("invalid proposed params: %s", err))
+	}
+
+	if msg.Proposer.Empty() {
+		return sdk.ErrInvalidAddress(msg.Proposer.String())
+	}
+
+	if !msg.InitialDeposit.IsValid() {
+		return sdk.ErrInvalidCoins(msg.InitialDeposit.String())
+	}
+
+	return nil
+}
+
+// GetSignBytes returns the sorted JSON bytes of the message used for signing.
+func (msg SubmitParamsProposalMsg) GetSignBytes() []byte {
+	return sdk.MustSortJSON(msgCodec.MustMarshalJSON(msg))
+}
+
+// GetSigners returns the proposer as the single signer of the message.
+func (msg SubmitParamsProposalMsg) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Proposer}
+}
@@ -0,0 +1,99 @@
+package types
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+type (
+	// objectCommitment defines the RLP encoded commitment to the changes made to
+	// a state object. It is hashed into the intermediate state root.
+	objectCommitment struct {
+		Address  ethcmn.Address
+		Nonce    uint64
+		Balance  *big.Int
+		CodeHash []byte
+		Deleted  uint8
+		Storage  []storageCommitment
+	}
+
+	// storageCommitment defines the commitment to a single storage change.
+	storageCommitment struct {
+		Key   ethcmn.Hash
+		Value ethcmn.Hash
+	}
+)
+
+// newObjectCommitment returns the commitment to the state object and its
+// pending storage changes. The storage changes are sorted by key.
+func newObjectCommitment(so *stateObject, deleted bool) objectCommitment {
+	if deleted {
+		// the balance, nonce, code and storage of a deleted object are irrelevant
+		return objectCommitment{Address: so.address, Balance: new(big.Int), Deleted: 1}
+	}
+
+	oc := objectCommitment{
+		Address:  so.address,
+		Nonce:    so.Nonce(),
+		Balance:  so.Balance(),
+		CodeHash: so.CodeHash(),
+		Storage:  make([]storageCommitment, 0, len(so.dirtyStorage)),
+	}
+
+	for key, value := range so.dirtyStorage {
+		oc.Storage = append(oc.Storage, storageCommitment{Key: key, Value: value})
+	}
+
+	sort.Slice(oc.Storage, func(i, j int) bool {
+		return bytes.Compare(oc.Storage[i].Key.Bytes(), oc.Storage[j].Key.Bytes()) < 0
+	})
+
+	return oc
+}
+
+// updateRoot updates the intermediate state root with the changes made to the
+// state objects of the given addresses. The new root is the hash of the
+// previous root followed by the commitment of each changed state object in
+// address order, so it is deterministic for a given sequence of state
+// transitions.
+//
+// It must be called prior to the state objects being finalized or committed as
+// their pending storage changes are part of the commitment.
+func (csdb *CommitStateDB) updateRoot(addrs map[ethcmn.Address]int, deleteEmptyObjects bool) {
+	sorted := make([]ethcmn.Address, 0, len(addrs))
+	for addr := range addrs {
+		if _, exist := csdb.stateObjects[addr]; exist {
+			sorted = append(sorted, addr)
+		}
+	}
+
+	if len(sorted) == 0 {
+		return
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].Bytes(), sorted[j].Bytes()) < 0
+	})
+
+	data := [][]byte{csdb.root.Bytes()}
+
+	for _, addr := range sorted {
+		so := csdb.stateObjects[addr]
+		deleted := so.suicided || (deleteEmptyObjects && so.empty())
+
+		bz, err := rlp.EncodeToBytes(newObjectCommitment(so, deleted))
+		if err != nil {
+			// a commitment consists of basic types only and cannot fail to encode
+			panic(err)
+		}
+
+		data = append(data, bz)
+	}
+
+	csdb.root = ethcrypto.Keccak256Hash(data...)
+}
@@ -44,6 +44,15 @@ type (
 		originStorage types.Storage // Storage cache of original entries to dedup rewrites
 		dirtyStorage  types.Storage // Storage entries that need to be flushed to disk
 
+		// storage keys whose original value was loaded from the legacy (hashed)
+		// storage key layout and must be migrated on commit
+		legacyStorage map[ethcmn.Hash]struct{}
+
+		// whether the storage migration marker of the account has been read and
+		// whether it is set, in which case the legacy layout is no longer read
+		legacyChecked  bool
+		legacyDisabled bool
+
 		// cache flags
 		//
 		// When an object is marked suicided it will be delete from the trie during
@@ -70,6 +79,7 @@ func newObject(db *CommitStateDB, accProto auth.Account) *stateObject {
 		address:       ethcmn.BytesToAddress(acc.Address.Bytes()),
 		originStorage: make(types.Storage),
 		dirtyStorage:  make(types.Storage),
+		legacyStorage: make(map[ethcmn.Hash]struct{}),
 	}
 }
 
@@ -77,8 +87,7 @@ func newObject(db *CommitStateDB, accProto auth.Account) *stateObject {
 // Setters
 // ----------------------------------------------------------------------------
 
-// SetState updates a value in account storage. Note, the key will be prefixed
-// with the address of the state object.
+// SetState updates a value in account storage.
 func (so *stateObject) SetState(db ethstate.Database, key, value ethcmn.Hash) {
 	// if the new value is the same as old, don't set
 	prev := so.GetState(db, key)
@@ -86,16 +95,14 @@ func (so *stateObject) SetState(db ethstate.Database, key, value ethcmn.Hash) {
 		return
 	}
 
-	prefixKey := so.GetStorageByAddressKey(key.Bytes())
-
 	// since the new value is different, update and journal the change
 	so.stateDB.journal.append(storageChange{
 		account:   &so.address,
-		key:       prefixKey,
+		key:       key,
 		prevValue: prev,
 	})
 
-	so.setState(prefixKey, value)
+	so.setState(key, value)
 }
 
 func (so *stateObject) setState(key, value ethcmn.Hash) {
@@ -136,7 +143,7 @@ func (so *stateObject) AddBalance(amount *big.Int) {
 		return
 	}
 
-	newBalance := so.account.Balance().Add(amt)
+	newBalance := so.account.Balance(so.stateDB.params.EVMDenom).Add(amt)
 	so.SetBalance(newBalance.BigInt())
 }
 
@@ -149,7 +156,7 @@ func (so *stateObject) SubBalance(amount *big.Int) {
 		return
 	}
 
-	newBalance := so.account.Balance().Sub(amt)
+	newBalance := so.account.Balance(so.stateDB.params.EVMDenom).Sub(amt)
 	so.SetBalance(newBalance.BigInt())
 }
 
@@ -159,14 +166,14 @@ func (so *stateObject) SetBalance(amount *big.Int) {
 
 	so.stateDB.journal.append(balanceChange{
 		account: &so.address,
-		prev:    so.account.Balance(),
+		prev:    so.account.Balance(so.stateDB.params.EVMDenom),
 	})
 
 	so.setBalance(amt)
 }
 
 func (so *stateObject) setBalance(amount sdk.Int) {
-	so.account.SetBalance(amount)
+	so.account.SetBalance(so.stateDB.params.EVMDenom, amount)
 }
 
 // SetNonce sets the state object's nonce (sequence number).
@@ -199,6 +206,21 @@ func (so *stateObject) commitState() {
 	ctx := so.stateDB.ctx
 	store := ctx.KVStore(so.stateDB.storageKey)
 
+	// migrate entries loaded from the legacy storage key layout even if they
+	// have not changed
+	for key := range so.legacyStorage {
+		delete(so.legacyStorage, key)
+		store.Delete(LegacyStorageKey(so.address, key))
+
+		// the entry no longer exists in the store, so the value it holds after
+		// commit must be written under the new key
+		if _, dirty := so.dirtyStorage[key]; !dirty {
+			so.dirtyStorage[key] = so.originStorage[key]
+		}
+
+		so.originStorage[key] = ethcmn.Hash{}
+	}
+
 	for key, value := range so.dirtyStorage {
 		delete(so.dirtyStorage, key)
 
@@ -211,21 +233,73 @@ func (so *stateObject) commitState() {
 
 		// delete empty values
 		if (value == ethcmn.Hash{}) {
-			store.Delete(key.Bytes())
+			store.Delete(StorageKey(so.address, key))
 			continue
 		}
 
-		store.Set(key.Bytes(), value.Bytes())
+		store.Set(StorageKey(so.address, key), value.Bytes())
 	}
 
 	// TODO: Set the account (storage) root (but we probably don't need this)
 }
 
-// commitCode persists the state object's code to the KVStore.
+// commitCode persists the state object's code to the KVStore and increments
+// the number of accounts referencing it. Code persisted before references were
+// counted is left untracked until MigrateCodeRefs is run.
 func (so *stateObject) commitCode() {
 	ctx := so.stateDB.ctx
 	store := ctx.KVStore(so.stateDB.codeKey)
-	store.Set(so.CodeHash(), so.code)
+
+	codeHash := so.CodeHash()
+	refKey := CodeRefCountKey(codeHash)
+
+	switch bz := store.Get(refKey); {
+	case bz != nil:
+		store.Set(refKey, encodeRefCount(decodeRefCount(bz)+1))
+
+	case store.Has(codeHash):
+		// untracked code
+
+	default:
+		store.Set(codeHash, so.code)
+		store.Set(refKey, encodeRefCount(1))
+	}
+}
+
+// deleteStorage removes all the storage of the state object from the KVStore.
+func (so *stateObject) deleteStorage() {
+	ctx := so.stateDB.ctx
+	store := ctx.KVStore(so.stateDB.storageKey)
+
+	// collect the keys first as the store must not be written to while being
+	// iterated over
+	var keys [][]byte
+
+	iter := sdk.KVStorePrefixIterator(store, AddressStoragePrefix(so.address))
+	for ; iter.Valid(); iter.Next() {
+		keys = append(keys, iter.Key())
+	}
+
+	iter.Close()
+
+	for _, key := range keys {
+		store.Delete(key)
+	}
+
+	// legacy (hashed) keys cannot be iterated over, only the known ones are
+	// removed and the remaining ones of a contract are no longer read
+	for key := range so.legacyStorage {
+		store.Delete(LegacyStorageKey(so.address, key))
+	}
+
+	if len(so.CodeHash()) != 0 && !bytes.Equal(so.CodeHash(), emptyCodeHash) {
+		store.Set(StorageMigratedKey(so.address), []byte{1})
+		so.legacyChecked, so.legacyDisabled = true, true
+	}
+
+	so.originStorage = make(types.Storage)
+	so.dirtyStorage = make(types.Storage)
+	so.legacyStorage = make(map[ethcmn.Hash]struct{})
 }
 
 // ----------------------------------------------------------------------------
@@ -239,7 +313,7 @@ func (so stateObject) Address() ethcmn.Address {
 
 // Balance returns the state object's current balance.
 func (so *stateObject) Balance() *big.Int {
-	return so.account.Balance().BigInt()
+	return so.account.Balance(so.stateDB.params.EVMDenom).BigInt()
 }
 
 // CodeHash returns the state object's code hash.
@@ -274,13 +348,10 @@ func (so *stateObject) Code(_ ethstate.Database) []byte {
 	return code
 }
 
-// GetState retrieves a value from the account storage trie. Note, the key will
-// be prefixed with the address of the state object.
+// GetState retrieves a value from the account storage.
 func (so *stateObject) GetState(db ethstate.Database, key ethcmn.Hash) ethcmn.Hash {
-	prefixKey := so.GetStorageByAddressKey(key.Bytes())
-
 	// if we have a dirty value for this state entry, return it
-	value, dirty := so.dirtyStorage[prefixKey]
+	value, dirty := so.dirtyStorage[key]
 	if dirty {
 		return value
 	}
@@ -289,13 +360,10 @@ func (so *stateObject) GetState(db ethstate.Database, key ethcmn.Hash) ethcmn.Ha
 	return so.GetCommittedState(db, key)
 }
 
-// GetCommittedState retrieves a value from the committed account storage trie.
-// Note, the key will be prefixed with the address of the state object.
+// GetCommittedState retrieves a value from the committed account storage.
 func (so *stateObject) GetCommittedState(_ ethstate.Database, key ethcmn.Hash) ethcmn.Hash {
-	prefixKey := so.GetStorageByAddressKey(key.Bytes())
-
 	// if we have the original value cached, return that
-	value, cached := so.originStorage[prefixKey]
+	value, cached := so.originStorage[key]
 	if cached {
 		return value
 	}
@@ -303,16 +371,36 @@ func (so *stateObject) GetCommittedState(_ ethstate.Database, key ethcmn.Hash) e
 	// otherwise load the value from the KVStore
 	ctx := so.stateDB.ctx
 	store := ctx.KVStore(so.stateDB.storageKey)
-	rawValue := store.Get(prefixKey.Bytes())
+	rawValue := store.Get(StorageKey(so.address, key))
+
+	// fallback to the legacy storage key layout for entries not yet migrated
+	if len(rawValue) == 0 && so.readsLegacyStorage(store) {
+		rawValue = store.Get(LegacyStorageKey(so.address, key))
+		if len(rawValue) > 0 {
+			so.legacyStorage[key] = struct{}{}
+		}
+	}
 
 	if len(rawValue) > 0 {
 		value.SetBytes(rawValue)
 	}
 
-	so.originStorage[prefixKey] = value
+	so.originStorage[key] = value
 	return value
 }
 
+// readsLegacyStorage returns whether entries missing from the account storage
+// must be looked up under the legacy storage key layout. The migration marker of
+// the account is read from the store only once per state object.
+func (so *stateObject) readsLegacyStorage(store sdk.KVStore) bool {
+	if !so.legacyChecked {
+		so.legacyChecked = true
+		so.legacyDisabled = store.Has(StorageMigratedKey(so.address))
+	}
+
+	return !so.legacyDisabled
+}
+
 // ----------------------------------------------------------------------------
 // Auxiliary
 // ----------------------------------------------------------------------------
@@ -322,11 +410,18 @@ func (so *stateObject) GetCommittedState(_ ethstate.Database, key ethcmn.Hash) e
 func (so *stateObject) ReturnGas(gas *big.Int) {}
 
 func (so *stateObject) deepCopy(db *CommitStateDB) *stateObject {
-	newStateObj := newObject(db, so.account)
+	newStateObj := newObject(db, so.account.Copy())
 
 	newStateObj.code = so.code
 	newStateObj.dirtyStorage = so.dirtyStorage.Copy()
 	newStateObj.originStorage = so.originStorage.Copy()
+
+	for key := range so.legacyStorage {
+		newStateObj.legacyStorage[key] = struct{}{}
+	}
+
+	newStateObj.legacyChecked = so.legacyChecked
+	newStateObj.legacyDisabled = so.legacyDisabled
 	newStateObj.suicided = so.suicided
 	newStateObj.dirtyCode = so.dirtyCode
 	newStateObj.deleted = so.deleted
@@ -334,10 +429,12 @@ func (so *stateObject) deepCopy(db *CommitStateDB) *stateObject {
 	return newStateObj
 }
 
-// empty returns whether the account is considered empty.
+// empty returns whether the account is considered empty. An account holding
+// coins of any denomination other than the EVM denomination is never empty so
+// that it is not deleted along with its coins (EIP158).
 func (so *stateObject) empty() bool {
 	return so.account.Sequence == 0 &&
-		so.account.Balance().Sign() == 0 &&
+		so.account.GetCoins().IsZero() &&
 		bytes.Equal(so.account.CodeHash, emptyCodeHash)
 }
 
@@ -352,15 +449,3 @@ func (so *stateObject) touch() {
 		so.stateDB.journal.dirty(so.address)
 	}
 }
-
-// GetStorageByAddressKey returns a hash of the composite key for a state
-// object's storage prefixed with it's address.
-func (so stateObject) GetStorageByAddressKey(key []byte) ethcmn.Hash {
-	prefix := so.Address().Bytes()
-	compositeKey := make([]byte, len(prefix)+len(key))
-
-	copy(compositeKey, prefix)
-	copy(compositeKey[len(prefix):], key)
-
-	return ethcrypto.Keccak256Hash(compositeKey)
-}
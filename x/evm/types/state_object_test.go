@@ -0,0 +1,192 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/params"
+
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+type testSetup struct {
+	ctx        sdk.Context
+	ak         auth.AccountKeeper
+	storageKey sdk.StoreKey
+	codeKey    sdk.StoreKey
+	stateDB    *CommitStateDB
+}
+
+func newTestSetup(t *testing.T) testSetup {
+	db := dbm.NewMemDB()
+
+	accKey := sdk.NewKVStoreKey("acc")
+	storageKey := sdk.NewKVStoreKey("storage")
+	codeKey := sdk.NewKVStoreKey("code")
+	paramsKey := sdk.NewKVStoreKey("params")
+	tParamsKey := sdk.NewTransientStoreKey("transient_params")
+
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(accKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(storageKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(codeKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(paramsKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(tParamsKey, sdk.StoreTypeTransient, db)
+	require.NoError(t, ms.LoadLatestVersion())
+
+	cdc := codec.New()
+	RegisterCodec(cdc)
+	types.RegisterCodec(cdc)
+	auth.RegisterCodec(cdc)
+	sdk.RegisterCodec(cdc)
+	codec.RegisterCrypto(cdc)
+
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "3"}, false, log.NewNopLogger())
+
+	ak := auth.NewAccountKeeper(cdc, accKey, types.ProtoBaseAccount)
+	pk := params.NewKeeper(cdc, paramsKey, tParamsKey)
+	paramSpace := pk.Subspace(DefaultParamspace).WithTypeTable(ParamTypeTable())
+
+	stateDB, err := NewCommitStateDB(ctx, ak, paramSpace, storageKey, codeKey)
+	require.NoError(t, err)
+
+	return testSetup{ctx: ctx, ak: ak, storageKey: storageKey, codeKey: codeKey, stateDB: stateDB}
+}
+
+func (ts testSetup) getAccount(addr ethcmn.Address) *types.Account {
+	acc := ts.ak.GetAccount(ts.ctx, sdk.AccAddress(addr.Bytes()))
+	if acc == nil {
+		return nil
+	}
+
+	return acc.(*types.Account)
+}
+
+func TestStateObjectBalance(t *testing.T) {
+	ts := newTestSetup(t)
+	addr := GenerateEthAddress()
+
+	// fund the account with a non-EVM denomination through the account keeper
+	acc := ts.ak.NewAccountWithAddress(ts.ctx, sdk.AccAddress(addr.Bytes()))
+	require.NoError(t, acc.SetCoins(sdk.Coins{sdk.NewInt64Coin("stake", 10)}))
+	ts.ak.SetAccount(ts.ctx, acc)
+
+	so := ts.stateDB.GetOrNewStateObject(addr).(*stateObject)
+
+	so.AddBalance(big.NewInt(100))
+	require.Equal(t, big.NewInt(100), so.Balance())
+
+	so.SubBalance(big.NewInt(30))
+	require.Equal(t, big.NewInt(70), so.Balance())
+
+	// require the balance change to be reverted
+	snapshot := ts.stateDB.Snapshot()
+	so.SetBalance(big.NewInt(5))
+	require.Equal(t, big.NewInt(5), so.Balance())
+
+	ts.stateDB.RevertToSnapshot(snapshot)
+	require.Equal(t, big.NewInt(70), so.Balance())
+
+	_, err := ts.stateDB.Commit(true)
+	require.NoError(t, err)
+
+	// require the committed account to hold both denominations
+	stored := ts.getAccount(addr)
+	require.NotNil(t, stored)
+	require.Equal(t, sdk.NewInt(70), stored.Balance(types.DenomDefault))
+	require.Equal(t, sdk.NewInt(10), stored.Balance("stake"))
+}
+
+func TestStateObjectNonce(t *testing.T) {
+	ts := newTestSetup(t)
+	addr := GenerateEthAddress()
+
+	ts.stateDB.CreateAccount(addr)
+	so := ts.stateDB.GetOrNewStateObject(addr).(*stateObject)
+
+	so.SetNonce(1)
+	require.Equal(t, uint64(1), so.Nonce())
+
+	snapshot := ts.stateDB.Snapshot()
+	so.SetNonce(5)
+	require.Equal(t, uint64(5), ts.stateDB.GetNonce(addr))
+
+	ts.stateDB.RevertToSnapshot(snapshot)
+	require.Equal(t, uint64(1), ts.stateDB.GetNonce(addr))
+
+	_, err := ts.stateDB.Commit(true)
+	require.NoError(t, err)
+
+	stored := ts.getAccount(addr)
+	require.NotNil(t, stored)
+	require.Equal(t, uint64(1), stored.Sequence)
+}
+
+func TestStateObjectCodeHash(t *testing.T) {
+	ts := newTestSetup(t)
+	addr := GenerateEthAddress()
+
+	ts.stateDB.CreateAccount(addr)
+	so := ts.stateDB.GetOrNewStateObject(addr).(*stateObject)
+	require.Equal(t, emptyCodeHash, so.CodeHash())
+
+	code := []byte("contract code")
+	codeHash := ethcrypto.Keccak256Hash(code)
+
+	snapshot := ts.stateDB.Snapshot()
+	ts.stateDB.SetCode(addr, code)
+	require.Equal(t, codeHash.Bytes(), so.CodeHash())
+	require.Equal(t, code, ts.stateDB.GetCode(addr))
+
+	ts.stateDB.RevertToSnapshot(snapshot)
+	require.Equal(t, emptyCodeHash, so.CodeHash())
+	require.Nil(t, ts.stateDB.GetCode(addr))
+
+	ts.stateDB.SetCode(addr, code)
+	ts.stateDB.SetNonce(addr, 1)
+
+	_, err := ts.stateDB.Commit(true)
+	require.NoError(t, err)
+
+	stored := ts.getAccount(addr)
+	require.NotNil(t, stored)
+	require.Equal(t, codeHash.Bytes(), stored.CodeHash)
+
+	// require the code to be loaded from the store by a new state object
+	require.NoError(t, ts.stateDB.Reset(ethcmn.Hash{}))
+	require.Equal(t, code, ts.stateDB.GetCode(addr))
+	require.Equal(t, codeHash, ts.stateDB.GetCodeHash(addr))
+}
+
+func TestStateObjectDeepCopy(t *testing.T) {
+	ts := newTestSetup(t)
+	addr := GenerateEthAddress()
+
+	ts.stateDB.AddBalance(addr, big.NewInt(100))
+	ts.stateDB.SetNonce(addr, 1)
+
+	cpy := ts.stateDB.Copy()
+
+	// require changes to the copy not to affect the original state
+	cpy.AddBalance(addr, big.NewInt(50))
+	cpy.SetNonce(addr, 2)
+	cpy.SetCode(addr, []byte("code"))
+
+	require.Equal(t, big.NewInt(150), cpy.GetBalance(addr))
+	require.Equal(t, big.NewInt(100), ts.stateDB.GetBalance(addr))
+	require.Equal(t, uint64(2), cpy.GetNonce(addr))
+	require.Equal(t, uint64(1), ts.stateDB.GetNonce(addr))
+	require.Equal(t, ethcmn.BytesToHash(emptyCodeHash), ts.stateDB.GetCodeHash(addr))
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 	"sort"
@@ -8,6 +9,9 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/params"
+
+	"github.com/cosmos/ethermint/types"
 
 	ethcmn "github.com/ethereum/go-ethereum/common"
 	ethstate "github.com/ethereum/go-ethereum/core/state"
@@ -34,9 +38,21 @@ type CommitStateDB struct {
 	ctx sdk.Context
 
 	ak         auth.AccountKeeper
+	paramSpace params.Subspace
 	storageKey sdk.StoreKey
 	codeKey    sdk.StoreKey
 
+	// EVM module parameters (e.g. the coin denomination of EVM balances)
+	params Params
+
+	// intermediate state root committing to all the state transitions
+	// finalized since the state was created (see updateRoot)
+	root ethcmn.Hash
+
+	// net change of the total supply of the EVM denomination written to the
+	// account mapper since the state was created (see SupplyDelta)
+	supplyDelta sdk.Int
+
 	// maps that hold 'live' objects, which will get modified while processing a
 	// state transition
 	stateObjects      map[ethcmn.Address]*stateObject
@@ -61,6 +77,9 @@ type CommitStateDB struct {
 	// by StateDB.Commit.
 	dbErr error
 
+	// error of the first contract code not allowed by the module parameters
+	codeErr error
+
 	// Journal of state modifications. This is the backbone of
 	// Snapshot and RevertToSnapshot.
 	journal        *journal
@@ -76,12 +95,19 @@ type CommitStateDB struct {
 //
 // CONTRACT: Stores used for state must be cache-wrapped as the ordering of the
 // key/value space matters in determining the merkle root.
-func NewCommitStateDB(ctx sdk.Context, ak auth.AccountKeeper, storageKey, codeKey sdk.StoreKey) (*CommitStateDB, error) {
+func NewCommitStateDB(
+	ctx sdk.Context, ak auth.AccountKeeper, paramSpace params.Subspace, storageKey, codeKey sdk.StoreKey,
+) (*CommitStateDB, error) {
+
 	return &CommitStateDB{
 		ctx:               ctx,
 		ak:                ak,
+		paramSpace:        paramSpace,
 		storageKey:        storageKey,
 		codeKey:           codeKey,
+		params:            GetParams(ctx, paramSpace),
+		root:              ethcmn.BytesToHash(ctx.BlockHeader().AppHash),
+		supplyDelta:       sdk.ZeroInt(),
 		stateObjects:      make(map[ethcmn.Address]*stateObject),
 		stateObjectsDirty: make(map[ethcmn.Address]struct{}),
 		logs:              make(map[ethcmn.Hash][]*ethtypes.Log),
@@ -136,6 +162,19 @@ func (csdb *CommitStateDB) SetState(addr ethcmn.Address, key, value ethcmn.Hash)
 
 // SetCode sets the code for a given account.
 func (csdb *CommitStateDB) SetCode(addr ethcmn.Address, code []byte) {
+	// The EVM cannot be made to fail a contract creation, so code that is not
+	// allowed by the module parameters is not set and the error is recorded
+	// instead (see CodeError). The error is journaled so that it is cleared if
+	// the creation is reverted.
+	if err := csdb.params.ValidateCode(code); err != nil {
+		if csdb.codeErr == nil {
+			csdb.journal.append(codeErrorChange{prev: csdb.codeErr})
+			csdb.codeErr = fmt.Errorf("invalid code for %s: %s", addr.Hex(), err)
+		}
+
+		return
+	}
+
 	so := csdb.GetOrNewStateObject(addr)
 	if so != nil {
 		so.SetCode(ethcrypto.Keccak256Hash(code), code)
@@ -312,10 +351,13 @@ func (csdb *CommitStateDB) StorageTrie(addr ethcmn.Address) ethstate.Trie {
 // Commit writes the state to the appropriate KVStores. For each state object
 // in the cache, it will either be removed, or have it's code set and/or it's
 // state (storage) updated. In addition, the state object (account) itself will
-// be written. Finally, the root hash (version) will be returned.
+// be written. Finally, the intermediate state root will be returned.
 func (csdb *CommitStateDB) Commit(deleteEmptyObjects bool) (root ethcmn.Hash, err error) {
 	defer csdb.clearJournalAndRefund()
 
+	// commit to the changes not yet finalized prior to writing them
+	csdb.updateRoot(csdb.journal.dirties, deleteEmptyObjects)
+
 	// remove dirty state object entries based on the journal
 	for addr := range csdb.journal.dirties {
 		csdb.stateObjectsDirty[addr] = struct{}{}
@@ -338,6 +380,10 @@ func (csdb *CommitStateDB) Commit(deleteEmptyObjects bool) (root ethcmn.Hash, er
 				so.dirtyCode = false
 			}
 
+			// write any pending storage changes so that committing does not
+			// depend on Finalize or IntermediateRoot being called beforehand
+			so.commitState()
+
 			// update the object in the KVStore
 			csdb.updateStateObject(so)
 		}
@@ -347,14 +393,17 @@ func (csdb *CommitStateDB) Commit(deleteEmptyObjects bool) (root ethcmn.Hash, er
 
 	// NOTE: Ethereum returns the trie merkle root here, but as commitment
 	// actually happens in the BaseApp at EndBlocker, we do not know the root at
-	// this time.
-	return
+	// this time. The intermediate state root is returned instead.
+	return csdb.root, nil
 }
 
 // Finalize finalizes the state objects (accounts) state by setting their state,
 // removing the csdb destructed objects and clearing the journal as well as the
 // refunds.
 func (csdb *CommitStateDB) Finalize(deleteEmptyObjects bool) {
+	// commit to the changes prior to the pending storage being written
+	csdb.updateRoot(csdb.journal.dirties, deleteEmptyObjects)
+
 	for addr := range csdb.journal.dirties {
 		so, exist := csdb.stateObjects[addr]
 		if !exist {
@@ -373,8 +422,14 @@ func (csdb *CommitStateDB) Finalize(deleteEmptyObjects bool) {
 		if so.suicided || (deleteEmptyObjects && so.empty()) {
 			csdb.deleteStateObject(so)
 		} else {
-			// Set all the dirty state storage items for the state object in the
-			// KVStore and finally set the account in the account mapper.
+			// Set the contract code and all the dirty state storage items for the
+			// state object in the KVStore and finally set the account in the
+			// account mapper.
+			if so.code != nil && so.dirtyCode {
+				so.commitCode()
+				so.dirtyCode = false
+			}
+
 			so.commitState()
 			csdb.updateStateObject(so)
 		}
@@ -392,24 +447,110 @@ func (csdb *CommitStateDB) Finalize(deleteEmptyObjects bool) {
 //
 // NOTE: The SDK has not concept or method of getting any intermediate merkle
 // root as commitment of the merkle-ized tree doesn't happen until the
-// BaseApps' EndBlocker.
+// BaseApps' EndBlocker. Instead, the returned root is a hash chain over the
+// state changes of every finalized transaction, starting from the application
+// hash of the block header. It is deterministic across nodes processing the
+// same transactions in the same order.
 func (csdb *CommitStateDB) IntermediateRoot(deleteEmptyObjects bool) ethcmn.Hash {
 	csdb.Finalize(deleteEmptyObjects)
 
-	return ethcmn.Hash{}
+	return csdb.root
 }
 
 // updateStateObject writes the given state object to the store.
 func (csdb *CommitStateDB) updateStateObject(so *stateObject) {
+	prevBalance := csdb.storedBalance(csdb.ak.GetAccount(csdb.ctx, so.account.Address))
+	csdb.supplyDelta = csdb.supplyDelta.Add(sdk.NewIntFromBigInt(so.Balance()).Sub(prevBalance))
+
 	csdb.ak.SetAccount(csdb.ctx, so.account)
 }
 
-// deleteStateObject removes the given state object from the state store.
+// deleteStateObject removes the given state object from the state store along
+// with all of its storage. The code of the persisted account is removed as well
+// if no other account references it.
 func (csdb *CommitStateDB) deleteStateObject(so *stateObject) {
 	so.deleted = true
+
+	acc := csdb.ak.GetAccount(csdb.ctx, so.account.Address)
+	csdb.supplyDelta = csdb.supplyDelta.Sub(csdb.storedBalance(acc))
+
+	if ethAcc, ok := acc.(*types.Account); ok {
+		csdb.releaseCode(ethAcc.CodeHash)
+	}
+
+	so.deleteStorage()
 	csdb.ak.RemoveAccount(csdb.ctx, so.account)
 }
 
+// storedBalance returns the balance of the EVM denomination of an account as
+// persisted by the account mapper, or zero if the account does not exist.
+func (csdb *CommitStateDB) storedBalance(acc auth.Account) sdk.Int {
+	if acc == nil {
+		return sdk.ZeroInt()
+	}
+
+	return acc.GetCoins().AmountOf(csdb.params.EVMDenom)
+}
+
+// releaseCode decrements the number of accounts referencing the code with the
+// given hash and removes the code once it is no longer referenced. Code
+// persisted before references were counted is never removed until
+// MigrateCodeRefs is run.
+func (csdb *CommitStateDB) releaseCode(codeHash []byte) {
+	if len(codeHash) == 0 || bytes.Equal(codeHash, emptyCodeHash) {
+		return
+	}
+
+	store := csdb.ctx.KVStore(csdb.codeKey)
+	refKey := CodeRefCountKey(codeHash)
+
+	bz := store.Get(refKey)
+	if bz == nil {
+		return
+	}
+
+	if count := decodeRefCount(bz); count > 1 {
+		store.Set(refKey, encodeRefCount(count-1))
+		return
+	}
+
+	store.Delete(refKey)
+	store.Delete(codeHash)
+}
+
+// MigrateCodeRefs starts counting the references to the code persisted before
+// references were counted. The references are counted from all the persisted
+// accounts, so it must only be run once, as part of the upgrade to reference
+// counted code and not while processing transactions.
+func (csdb *CommitStateDB) MigrateCodeRefs() {
+	counts := make(map[string]uint64)
+
+	csdb.ak.IterateAccounts(csdb.ctx, func(acc auth.Account) bool {
+		ethAcc, ok := acc.(*types.Account)
+		if ok && len(ethAcc.CodeHash) != 0 && !bytes.Equal(ethAcc.CodeHash, emptyCodeHash) {
+			counts[string(ethAcc.CodeHash)]++
+		}
+
+		return false
+	})
+
+	// set the counts in a deterministic order
+	codeHashes := make([]string, 0, len(counts))
+	for codeHash := range counts {
+		codeHashes = append(codeHashes, codeHash)
+	}
+
+	sort.Strings(codeHashes)
+
+	store := csdb.ctx.KVStore(csdb.codeKey)
+	for _, codeHash := range codeHashes {
+		refKey := CodeRefCountKey([]byte(codeHash))
+		if !store.Has(refKey) && store.Has([]byte(codeHash)) {
+			store.Set(refKey, encodeRefCount(counts[codeHash]))
+		}
+	}
+}
+
 // ----------------------------------------------------------------------------
 // Snapshotting
 // ----------------------------------------------------------------------------
@@ -476,6 +617,24 @@ func (csdb *CommitStateDB) Error() error {
 	return csdb.dbErr
 }
 
+// CodeError returns the error of the first contract code set since the state
+// was created, and not reverted since, that is not allowed by the EVM module
+// parameters. Such code is not set, and the execution that set it must be
+// discarded.
+func (csdb *CommitStateDB) CodeError() error {
+	return csdb.codeErr
+}
+
+// SupplyDelta returns the net change of the total supply of the EVM
+// denomination caused by the state transitions written to the account mapper
+// (i.e. finalized or committed) since the state was created. Value transfers,
+// gas payments and SELFDESTRUCT payouts only move balances between accounts,
+// so any other value means the EVM minted or burned coins (e.g. a contract
+// self-destructing with itself as the beneficiary).
+func (csdb *CommitStateDB) SupplyDelta() sdk.Int {
+	return csdb.supplyDelta
+}
+
 // Suicide marks the given account as suicided and clears the account balance.
 //
 // The account's state object is still available until the state is committed,
@@ -502,6 +661,7 @@ func (csdb *CommitStateDB) Suicide(addr ethcmn.Address) bool {
 // the underlying account mapper and store keys to avoid reloading data for the
 // next operations.
 func (csdb *CommitStateDB) Reset(root ethcmn.Hash) error {
+	csdb.root = root
 	csdb.stateObjects = make(map[ethcmn.Address]*stateObject)
 	csdb.stateObjectsDirty = make(map[ethcmn.Address]struct{})
 	csdb.thash = ethcmn.Hash{}
@@ -557,8 +717,13 @@ func (csdb *CommitStateDB) Copy() ethstate.StateDB {
 	state := &CommitStateDB{
 		ctx:               csdb.ctx,
 		ak:                csdb.ak,
+		paramSpace:        csdb.paramSpace,
 		storageKey:        csdb.storageKey,
 		codeKey:           csdb.codeKey,
+		params:            csdb.params,
+		root:              csdb.root,
+		supplyDelta:       csdb.supplyDelta,
+		codeErr:           csdb.codeErr,
 		stateObjects:      make(map[ethcmn.Address]*stateObject, len(csdb.journal.dirties)),
 		stateObjectsDirty: make(map[ethcmn.Address]struct{}, len(csdb.journal.dirties)),
 		refund:            csdb.refund,
@@ -609,30 +774,38 @@ func (csdb *CommitStateDB) Copy() ethstate.StateDB {
 	return state
 }
 
-// ForEachStorage iterates over each storage items, all invokes the provided
-// callback on each key, value pair .
+// ForEachStorage iterates over each committed storage item of the given
+// account and invokes the provided callback on each key, value pair. Pending
+// (dirty) values take precedence over the committed ones. The iteration stops
+// when the callback returns false.
 func (csdb *CommitStateDB) ForEachStorage(addr ethcmn.Address, cb func(key, value ethcmn.Hash) bool) {
 	so := csdb.getStateObject(addr)
 	if so == nil {
 		return
 	}
 
+	prefix := AddressStoragePrefix(so.Address())
 	store := csdb.ctx.KVStore(csdb.storageKey)
-	iter := sdk.KVStorePrefixIterator(store, so.Address().Bytes())
+	iter := sdk.KVStorePrefixIterator(store, prefix)
+	defer iter.Close()
 
 	for ; iter.Valid(); iter.Next() {
-		key := ethcmn.BytesToHash(iter.Key())
-		value := iter.Value()
-
-		if value, dirty := so.dirtyStorage[key]; dirty {
-			cb(key, value)
+		// skip any legacy (hashed) key that happens to share the prefix
+		if len(iter.Key()) != len(prefix)+ethcmn.HashLength {
 			continue
 		}
 
-		cb(key, ethcmn.BytesToHash(value))
-	}
+		key := ethcmn.BytesToHash(iter.Key()[len(prefix):])
+		value := ethcmn.BytesToHash(iter.Value())
 
-	iter.Close()
+		if dirtyValue, dirty := so.dirtyStorage[key]; dirty {
+			value = dirtyValue
+		}
+
+		if !cb(key, value) {
+			return
+		}
+	}
 }
 
 // GetOrNewStateObject retrieves a state object or create a new state object if
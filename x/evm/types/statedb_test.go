@@ -0,0 +1,374 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/ethermint/types"
+
+	ethcmn "github.com/ethereum/go-ethereum/common"
+	ethstate "github.com/ethereum/go-ethereum/core/state"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testAddr1 = ethcmn.HexToAddress("0x1000000000000000000000000000000000000001")
+	testAddr2 = ethcmn.HexToAddress("0x2000000000000000000000000000000000000002")
+	testAddr3 = ethcmn.HexToAddress("0x3000000000000000000000000000000000000003")
+)
+
+// testTxs defines a set of state transitions, each simulating a transaction.
+var testTxs = []func(*CommitStateDB){
+	func(csdb *CommitStateDB) {
+		csdb.AddBalance(testAddr1, big.NewInt(1000))
+		csdb.SetNonce(testAddr1, 1)
+	},
+	func(csdb *CommitStateDB) {
+		csdb.SubBalance(testAddr1, big.NewInt(100))
+		csdb.AddBalance(testAddr2, big.NewInt(100))
+		csdb.SetNonce(testAddr1, 2)
+		csdb.SetNonce(testAddr2, 1)
+		csdb.SetCode(testAddr2, []byte("contract code"))
+		csdb.SetState(testAddr2, ethcmn.BytesToHash([]byte{0x1}), ethcmn.BytesToHash([]byte("value1")))
+		csdb.SetState(testAddr2, ethcmn.BytesToHash([]byte{0x2}), ethcmn.BytesToHash([]byte("value2")))
+	},
+	func(csdb *CommitStateDB) {
+		// overwrite, delete and revert storage changes
+		csdb.SetState(testAddr2, ethcmn.BytesToHash([]byte{0x1}), ethcmn.BytesToHash([]byte("value3")))
+		csdb.SetState(testAddr2, ethcmn.BytesToHash([]byte{0x2}), ethcmn.Hash{})
+
+		snapshot := csdb.Snapshot()
+		csdb.SetState(testAddr2, ethcmn.BytesToHash([]byte{0x3}), ethcmn.BytesToHash([]byte("reverted")))
+		csdb.AddBalance(testAddr3, big.NewInt(1))
+		csdb.RevertToSnapshot(snapshot)
+
+		csdb.SetState(testAddr2, ethcmn.BytesToHash([]byte{0x4}), ethcmn.BytesToHash([]byte("value4")))
+	},
+}
+
+// storeContents returns all the key/value pairs of the given store.
+func storeContents(ctx sdk.Context, key sdk.StoreKey) map[string][]byte {
+	return prefixContents(ctx, key, nil)
+}
+
+// prefixContents returns all the key/value pairs of the given store with the
+// given key prefix.
+func prefixContents(ctx sdk.Context, key sdk.StoreKey, prefix []byte) map[string][]byte {
+	contents := make(map[string][]byte)
+
+	iter := sdk.KVStorePrefixIterator(ctx.KVStore(key), prefix)
+	defer iter.Close()
+
+	for ; iter.Valid(); iter.Next() {
+		contents[string(iter.Key())] = iter.Value()
+	}
+
+	return contents
+}
+
+func TestCommitStateDBCommitFlows(t *testing.T) {
+	testCases := []struct {
+		name  string
+		apply func(csdb *CommitStateDB)
+	}{
+		{
+			"commit only",
+			func(csdb *CommitStateDB) {
+				for _, tx := range testTxs {
+					tx(csdb)
+				}
+			},
+		},
+		{
+			"finalize then commit",
+			func(csdb *CommitStateDB) {
+				for _, tx := range testTxs {
+					tx(csdb)
+				}
+
+				csdb.Finalize(true)
+			},
+		},
+		{
+			"intermediate root per transaction then commit",
+			func(csdb *CommitStateDB) {
+				for _, tx := range testTxs {
+					tx(csdb)
+					csdb.IntermediateRoot(true)
+				}
+			},
+		},
+	}
+
+	var (
+		expDump    ethstate.Dump
+		expStorage map[string][]byte
+	)
+
+	for i, tc := range testCases {
+		ts := newTestSetup(t)
+
+		tc.apply(ts.stateDB)
+
+		_, err := ts.stateDB.Commit(true)
+		require.NoError(t, err, tc.name)
+
+		// require the committed state to be visible to a fresh StateDB
+		require.NoError(t, ts.stateDB.Reset(ethcmn.Hash{}))
+		require.Equal(t, ethcmn.BytesToHash([]byte("value3")), ts.stateDB.GetState(testAddr2, ethcmn.BytesToHash([]byte{0x1})), tc.name)
+		require.Equal(t, ethcmn.Hash{}, ts.stateDB.GetState(testAddr2, ethcmn.BytesToHash([]byte{0x2})), tc.name)
+		require.Equal(t, ethcmn.Hash{}, ts.stateDB.GetState(testAddr2, ethcmn.BytesToHash([]byte{0x3})), tc.name)
+		require.Equal(t, ethcmn.BytesToHash([]byte("value4")), ts.stateDB.GetState(testAddr2, ethcmn.BytesToHash([]byte{0x4})), tc.name)
+		require.Equal(t, big.NewInt(900), ts.stateDB.GetBalance(testAddr1), tc.name)
+		require.False(t, ts.stateDB.Exist(testAddr3), tc.name)
+
+		dump := ts.stateDB.RawDump()
+		storage := storeContents(ts.ctx, ts.storageKey)
+		require.Len(t, storage, 2, tc.name)
+
+		// require all flows to result in the same state
+		if i == 0 {
+			expDump, expStorage = dump, storage
+			continue
+		}
+
+		require.Equal(t, expDump, dump, tc.name)
+		require.Equal(t, expStorage, storage, tc.name)
+	}
+}
+
+func TestCommitStateDBDeleteStateObject(t *testing.T) {
+	ts := newTestSetup(t)
+	codeStore := ts.ctx.KVStore(ts.codeKey)
+
+	code := []byte("contract code")
+	codeHash := ethcrypto.Keccak256(code)
+	slot := ethcmn.BytesToHash([]byte{0x1})
+	value := ethcmn.BytesToHash([]byte("value"))
+
+	// create two contracts sharing the same code
+	for _, addr := range []ethcmn.Address{testAddr1, testAddr2} {
+		ts.stateDB.SetNonce(addr, 1)
+		ts.stateDB.SetCode(addr, code)
+		ts.stateDB.SetState(addr, slot, value)
+	}
+
+	_, err := ts.stateDB.Commit(true)
+	require.NoError(t, err)
+	require.Equal(t, code, codeStore.Get(codeHash))
+	require.Equal(t, encodeRefCount(2), codeStore.Get(CodeRefCountKey(codeHash)))
+
+	// require the storage of a suicided contract to be removed while its code
+	// is kept as long as another contract references it
+	require.True(t, ts.stateDB.Suicide(testAddr1))
+
+	_, err = ts.stateDB.Commit(true)
+	require.NoError(t, err)
+	require.Empty(t, prefixContents(ts.ctx, ts.storageKey, AddressStoragePrefix(testAddr1)))
+	require.Len(t, prefixContents(ts.ctx, ts.storageKey, AddressStoragePrefix(testAddr2)), 1)
+	require.Equal(t, code, codeStore.Get(codeHash))
+	require.Equal(t, encodeRefCount(1), codeStore.Get(CodeRefCountKey(codeHash)))
+
+	// require a contract recreated at the same address not to see the old storage
+	require.NoError(t, ts.stateDB.Reset(ethcmn.Hash{}))
+	ts.stateDB.CreateAccount(testAddr1)
+	require.Equal(t, ethcmn.Hash{}, ts.stateDB.GetState(testAddr1, slot))
+
+	// require the code to be removed once it is no longer referenced
+	require.True(t, ts.stateDB.Suicide(testAddr2))
+
+	_, err = ts.stateDB.Commit(true)
+	require.NoError(t, err)
+	require.Empty(t, prefixContents(ts.ctx, ts.storageKey, AddressStoragePrefix(testAddr2)))
+	require.Nil(t, codeStore.Get(codeHash))
+	require.Nil(t, codeStore.Get(CodeRefCountKey(codeHash)))
+}
+
+func TestCommitStateDBDeleteEmptyObject(t *testing.T) {
+	ts := newTestSetup(t)
+
+	slot := ethcmn.BytesToHash([]byte{0x1})
+
+	ts.stateDB.SetNonce(testAddr1, 1)
+	ts.stateDB.SetState(testAddr1, slot, ethcmn.BytesToHash([]byte("value")))
+
+	_, err := ts.stateDB.Commit(true)
+	require.NoError(t, err)
+	require.Len(t, prefixContents(ts.ctx, ts.storageKey, AddressStoragePrefix(testAddr1)), 1)
+
+	// require the storage of an account deleted for being empty to be removed
+	ts.stateDB.SetNonce(testAddr1, 0)
+
+	_, err = ts.stateDB.Commit(true)
+	require.NoError(t, err)
+	require.False(t, ts.stateDB.Exist(testAddr1))
+	require.Empty(t, prefixContents(ts.ctx, ts.storageKey, AddressStoragePrefix(testAddr1)))
+}
+
+func TestCommitStateDBUntrackedCode(t *testing.T) {
+	ts := newTestSetup(t)
+	codeStore := ts.ctx.KVStore(ts.codeKey)
+
+	// code and an account referencing it persisted before references were
+	// counted
+	code := []byte("contract code")
+	codeHash := ethcrypto.Keccak256(code)
+	codeStore.Set(codeHash, code)
+
+	acc := ts.ak.NewAccountWithAddress(ts.ctx, sdk.AccAddress(testAddr2.Bytes())).(*types.Account)
+	acc.CodeHash = codeHash
+	require.NoError(t, acc.SetSequence(1))
+	ts.ak.SetAccount(ts.ctx, acc)
+
+	// require untracked code to be left untouched while processing transactions
+	ts.stateDB.SetNonce(testAddr1, 1)
+	ts.stateDB.SetCode(testAddr1, code)
+
+	_, err := ts.stateDB.Commit(true)
+	require.NoError(t, err)
+	require.Nil(t, codeStore.Get(CodeRefCountKey(codeHash)))
+
+	require.True(t, ts.stateDB.Suicide(testAddr1))
+
+	_, err = ts.stateDB.Commit(true)
+	require.NoError(t, err)
+	require.Equal(t, code, codeStore.Get(codeHash))
+	require.Nil(t, codeStore.Get(CodeRefCountKey(codeHash)))
+
+	// require the references to be counted once migrated
+	ts.stateDB.SetNonce(testAddr1, 1)
+	ts.stateDB.SetCode(testAddr1, code)
+
+	_, err = ts.stateDB.Commit(true)
+	require.NoError(t, err)
+
+	ts.stateDB.MigrateCodeRefs()
+	require.Equal(t, encodeRefCount(2), codeStore.Get(CodeRefCountKey(codeHash)))
+
+	// require the code to be removed along with its last reference
+	require.NoError(t, ts.stateDB.Reset(ethcmn.Hash{}))
+	require.True(t, ts.stateDB.Suicide(testAddr1))
+
+	_, err = ts.stateDB.Commit(true)
+	require.NoError(t, err)
+	require.Equal(t, encodeRefCount(1), codeStore.Get(CodeRefCountKey(codeHash)))
+
+	require.True(t, ts.stateDB.Suicide(testAddr2))
+
+	_, err = ts.stateDB.Commit(true)
+	require.NoError(t, err)
+	require.Nil(t, codeStore.Get(codeHash))
+	require.Nil(t, codeStore.Get(CodeRefCountKey(codeHash)))
+}
+
+func TestCommitStateDBIntermediateRoot(t *testing.T) {
+	applyTxs := func(txs []func(*CommitStateDB)) []ethcmn.Hash {
+		ts := newTestSetup(t)
+
+		roots := make([]ethcmn.Hash, len(txs))
+		for i, tx := range txs {
+			tx(ts.stateDB)
+			roots[i] = ts.stateDB.IntermediateRoot(true)
+		}
+
+		return roots
+	}
+
+	// require the roots to be deterministic
+	roots := applyTxs(testTxs)
+	require.Equal(t, roots, applyTxs(testTxs))
+
+	// require every transaction to change the root
+	prevRoot := ethcmn.Hash{}
+	for i, root := range roots {
+		require.NotEqual(t, prevRoot, root, "test case #%d", i)
+		prevRoot = root
+	}
+
+	// require a different state transition to result in a different root
+	txs := append([]func(*CommitStateDB){}, testTxs...)
+	txs[1] = func(csdb *CommitStateDB) {
+		testTxs[1](csdb)
+		csdb.SetState(testAddr2, ethcmn.BytesToHash([]byte{0x2}), ethcmn.BytesToHash([]byte("other")))
+	}
+
+	otherRoots := applyTxs(txs)
+	require.Equal(t, roots[0], otherRoots[0])
+	require.NotEqual(t, roots[1], otherRoots[1])
+	require.NotEqual(t, roots[2], otherRoots[2])
+
+	// require a transaction without state changes to keep the root
+	ts := newTestSetup(t)
+	testTxs[0](ts.stateDB)
+	root := ts.stateDB.IntermediateRoot(true)
+	require.Equal(t, root, ts.stateDB.IntermediateRoot(true))
+
+	// require commit to fold in the pending changes and return the root
+	testTxs[1](ts.stateDB)
+	commitRoot, err := ts.stateDB.Commit(true)
+	require.NoError(t, err)
+	require.Equal(t, roots[1], commitRoot)
+
+	// require the root to be carried over to copies and set on reset
+	require.Equal(t, commitRoot, ts.stateDB.Copy().IntermediateRoot(true))
+	require.NoError(t, ts.stateDB.Reset(root))
+	require.Equal(t, root, ts.stateDB.IntermediateRoot(true))
+}
+
+func TestCommitStateDBSupplyDelta(t *testing.T) {
+	ts := newTestSetup(t)
+
+	// require minting to be tracked once finalized
+	ts.stateDB.AddBalance(testAddr1, big.NewInt(1000))
+	require.True(t, ts.stateDB.SupplyDelta().IsZero())
+
+	ts.stateDB.Finalize(true)
+	require.True(t, sdk.NewInt(1000).Equal(ts.stateDB.SupplyDelta()))
+
+	// require transfers, including self-destruct payouts, to conserve the supply
+	ts.stateDB.SubBalance(testAddr1, big.NewInt(100))
+	ts.stateDB.AddBalance(testAddr2, big.NewInt(100))
+
+	ts.stateDB.AddBalance(testAddr3, ts.stateDB.GetBalance(testAddr2))
+	require.True(t, ts.stateDB.Suicide(testAddr2))
+
+	_, err := ts.stateDB.Commit(true)
+	require.NoError(t, err)
+	require.True(t, sdk.NewInt(1000).Equal(ts.stateDB.SupplyDelta()))
+
+	// require a self-destruct without beneficiary to burn the balance
+	require.True(t, ts.stateDB.Suicide(testAddr3))
+
+	_, err = ts.stateDB.Commit(true)
+	require.NoError(t, err)
+	require.True(t, sdk.NewInt(900).Equal(ts.stateDB.SupplyDelta()))
+}
+
+func TestCommitStateDBCodeError(t *testing.T) {
+	ts := newTestSetup(t)
+	ts.stateDB.params.MaxCodeSize = 1
+
+	// require disallowed code not to be set and its error to be cleared once
+	// the creation setting it is reverted
+	id := ts.stateDB.Snapshot()
+
+	ts.stateDB.SetCode(testAddr1, []byte("contract code"))
+	require.Error(t, ts.stateDB.CodeError())
+	require.Zero(t, ts.stateDB.GetCodeSize(testAddr1))
+
+	ts.stateDB.RevertToSnapshot(id)
+	require.NoError(t, ts.stateDB.CodeError())
+
+	// require the first error not reverted to be kept
+	ts.stateDB.SetCode(testAddr1, []byte("contract code"))
+	err := ts.stateDB.CodeError()
+	require.Error(t, err)
+
+	id = ts.stateDB.Snapshot()
+	ts.stateDB.SetCode(testAddr2, []byte("other contract code"))
+	ts.stateDB.RevertToSnapshot(id)
+	require.Equal(t, err, ts.stateDB.CodeError())
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/binary"
 	"fmt"
 
 	"github.com/cosmos/ethermint/crypto"
@@ -45,3 +46,14 @@ func rlpHash(x interface{}) (hash ethcmn.Hash) {
 
 	return
 }
+
+func encodeRefCount(count uint64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, count)
+
+	return bz
+}
+
+func decodeRefCount(bz []byte) uint64 {
+	return binary.BigEndian.Uint64(bz)
+}